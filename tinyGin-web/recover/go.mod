@@ -1,6 +1,6 @@
 module middleware
 
-go 1.16
+go 1.18
 
 replace tinyGin => ./tinyGin
 
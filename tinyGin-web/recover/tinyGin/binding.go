@@ -0,0 +1,207 @@
+package tinyGin
+
+import (
+	"encoding"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Bind fills obj, a pointer to a struct, from the body, the query and the route params
+// in that order, so a route param wins, and then validates it.
+// Fields are matched by the json or form tag for the body, the query tag for the query
+// and the uri tag for the params
+func (c *Context) Bind(obj interface{}) error {
+	if err := c.BindBody(obj); err != nil {
+		return err
+	}
+	if err := c.BindQuery(obj); err != nil {
+		return err
+	}
+	if err := c.BindURI(obj); err != nil {
+		return err
+	}
+	return Validate(obj)
+}
+
+// ErrUnsupportedMediaType is returned by BindBody for a body which is neither JSON nor a form,
+// it is written as 415
+var ErrUnsupportedMediaType = errors.New("unsupported media type")
+
+// BindBody decodes the body as JSON or as a form, by Content-Type, an empty body is skipped.
+// A body without Content-Type is decoded as JSON
+func (c *Context) BindBody(obj interface{}) error {
+	if c.Req.Body == nil || c.Req.Body == http.NoBody || c.Req.ContentLength == 0 {
+		return nil
+	}
+	contentType, _, _ := mime.ParseMediaType(c.Req.Header.Get("Content-Type"))
+	switch {
+	case contentType == "application/x-www-form-urlencoded" || contentType == "multipart/form-data":
+		return c.BindForm(obj)
+	case contentType == "" || contentType == "application/json" || strings.HasSuffix(contentType, "+json"):
+		return c.BindJSON(obj)
+	}
+	return &BindingError{Source: "body", Err: fmt.Errorf("%w %s", ErrUnsupportedMediaType, contentType)}
+}
+
+// BindJSON decodes the JSON body into obj
+func (c *Context) BindJSON(obj interface{}) error {
+	return c.DecodeJSON(obj)
+}
+
+// BindForm fills obj from the form body, by the form tag
+func (c *Context) BindForm(obj interface{}) error {
+	if err := c.Req.ParseMultipartForm(32 << 20); err != nil && !errors.Is(err, http.ErrNotMultipart) {
+		return &BindingError{Source: "body", Err: err}
+	}
+	return bindValues(obj, "form", c.Req.PostForm)
+}
+
+// BindQuery fills obj from the query, by the query tag
+func (c *Context) BindQuery(obj interface{}) error {
+	return bindValues(obj, "query", c.Req.URL.Query())
+}
+
+// BindURI fills obj from the route params, by the uri tag
+func (c *Context) BindURI(obj interface{}) error {
+	values := make(map[string][]string, len(c.Params))
+	for key, value := range c.Params {
+		values[key] = []string{value}
+	}
+	return bindValues(obj, "uri", values)
+}
+
+// BindingError is returned when a request can not be decoded into the target
+type BindingError struct {
+	Source string // body, query or uri
+	Field  string
+	Err    error
+}
+
+func (e *BindingError) Error() string {
+	if e.Field != "" {
+		return fmt.Sprintf("invalid %s field %s: %v", e.Source, e.Field, e.Err)
+	}
+	return fmt.Sprintf("invalid %s: %v", e.Source, e.Err)
+}
+
+func (e *BindingError) Unwrap() error {
+	return e.Err
+}
+
+// bindValues sets the fields of obj tagged with tag whose name is in values
+func bindValues(obj interface{}, tag string, values map[string][]string) error {
+	v := reflect.ValueOf(obj)
+	if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("tinyGin: binding target must be a pointer to struct, got %T", obj)
+	}
+	return bindStruct(v.Elem(), tag, values)
+}
+
+func bindStruct(v reflect.Value, tag string, values map[string][]string) error {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.Anonymous && f.Type.Kind() == reflect.Struct {
+			if err := bindStruct(v.Field(i), tag, values); err != nil {
+				return err
+			}
+			continue
+		}
+		name, ok := f.Tag.Lookup(tag)
+		if !ok || f.PkgPath != "" {
+			continue
+		}
+		name = strings.Split(name, ",")[0]
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		raw, ok := values[name]
+		if !ok || len(raw) == 0 {
+			continue
+		}
+		if err := setField(v.Field(i), raw); err != nil {
+			return &BindingError{Source: tag, Field: name, Err: err}
+		}
+	}
+	return nil
+}
+
+var textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+
+func setField(field reflect.Value, raw []string) error {
+	if field.Kind() == reflect.Ptr {
+		value := reflect.New(field.Type().Elem())
+		if err := setField(value.Elem(), raw); err != nil {
+			return err
+		}
+		field.Set(value)
+		return nil
+	}
+	if field.Kind() == reflect.Slice && field.Type().Elem().Kind() != reflect.Uint8 {
+		slice := reflect.MakeSlice(field.Type(), len(raw), len(raw))
+		for i, s := range raw {
+			if err := setValue(slice.Index(i), s); err != nil {
+				return err
+			}
+		}
+		field.Set(slice)
+		return nil
+	}
+	return setValue(field, raw[0])
+}
+
+func setValue(field reflect.Value, s string) error {
+	if field.CanAddr() && field.Addr().Type().Implements(textUnmarshalerType) {
+		return field.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(s))
+	}
+	if field.Type() == reflect.TypeOf(time.Duration(0)) {
+		d, err := time.ParseDuration(s)
+		if err == nil {
+			field.SetInt(int64(d))
+		}
+		return err
+	}
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(s)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return err
+		}
+		field.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(s, 10, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(s, 10, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		n, err := strconv.ParseFloat(s, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetFloat(n)
+	case reflect.Slice:
+		// []byte
+		field.SetBytes([]byte(s))
+	default:
+		return fmt.Errorf("unsupported type %s", field.Type())
+	}
+	return nil
+}
@@ -0,0 +1,145 @@
+package tinyGin
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+type bindAddress struct {
+	City string `json:"city" validate:"required"`
+}
+
+type bindUser struct {
+	ID        int           `uri:"id"`
+	Name      string        `json:"name" form:"name" validate:"required,min=2,max=8"`
+	Email     string        `json:"email" form:"email" validate:"email"`
+	Role      string        `json:"role" query:"role" validate:"oneof=admin user"`
+	Tags      []string      `query:"tag"`
+	Since     *time.Time    `query:"since"`
+	Timeout   time.Duration `query:"timeout"`
+	Addresses []bindAddress `json:"addresses"`
+}
+
+func bindRequest(method, target, contentType, body string, params map[string]string) *Context {
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	req.Header.Set("Content-Type", contentType)
+	c := newContext(httptest.NewRecorder(), req)
+	c.Params = params
+	return c
+}
+
+func TestContext_Bind(t *testing.T) {
+	c := bindRequest("POST", "/users/7?role=admin&tag=a&tag=b&since=2024-01-02T03:04:05Z&timeout=1m",
+		"application/json", `{"id": 1, "name": "tom", "role": "user", "addresses": [{"city": "Paris"}]}`,
+		map[string]string{"id": "7"})
+	var u bindUser
+	if err := c.Bind(&u); err != nil {
+		t.Fatal(err)
+	}
+	if u.ID != 7 || u.Name != "tom" || u.Role != "admin" || fmt.Sprint(u.Tags) != "[a b]" ||
+		u.Since == nil || u.Since.Year() != 2024 || u.Timeout != time.Minute || u.Addresses[0].City != "Paris" {
+		t.Fatalf("unexpected binding %+v", u)
+	}
+
+	c = bindRequest("POST", "/users", "application/x-www-form-urlencoded", "name=jerry&email=jerry@example.com", nil)
+	u = bindUser{}
+	if err := c.Bind(&u); err != nil || u.Name != "jerry" || u.Email != "jerry@example.com" {
+		t.Fatalf("expect the form to be bound, but got %+v %v", u, err)
+	}
+
+	c = bindRequest("POST", "/users?timeout=soon", "application/json", `{"name": "tom"}`, nil)
+	var bindingErr *BindingError
+	if err := c.Bind(&bindUser{}); !errors.As(err, &bindingErr) || bindingErr.Field != "timeout" {
+		t.Fatalf("expect a binding error of timeout, but got %v", err)
+	}
+	if err := bindRequest("POST", "/", "application/json", `{"name":`, nil).Bind(&bindUser{}); !errors.As(err, &bindingErr) {
+		t.Fatalf("expect a binding error of the body, but got %v", err)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	err := Validate(&bindUser{Name: "a", Email: "nobody", Role: "root", Addresses: []bindAddress{{}}})
+	var errs ValidationErrors
+	if !errors.As(err, &errs) {
+		t.Fatalf("expect ValidationErrors, but got %v", err)
+	}
+	want := map[string]string{
+		"name":              "name must be at least 2",
+		"email":             "email must be an email address",
+		"role":              "role must be one of admin user",
+		"addresses[0].city": "addresses[0].city is required",
+	}
+	if fmt.Sprint(errs.Map()) != fmt.Sprint(want) {
+		t.Fatalf("expect %v, but got %v", want, errs.Map())
+	}
+	if err := Validate(&bindUser{Name: "tom"}); err != nil {
+		t.Fatalf("expect empty optional fields to be valid, but got %v", err)
+	}
+}
+
+func TestBindingErrorStatus(t *testing.T) {
+	r := New()
+	r.Use(ErrorHandler())
+	r.POST("/users", WrapE(func(c *Context) error {
+		var u bindUser
+		return c.Bind(&u)
+	}))
+	for body, code := range map[string]int{`{"name": 1}`: http.StatusBadRequest, `{"name": "a"}`: http.StatusUnprocessableEntity} {
+		req := httptest.NewRequest("POST", "/users", strings.NewReader(body))
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		if w.Code != code {
+			t.Fatalf("%s: expect %d, but got %d %s", body, code, w.Code, w.Body.String())
+		}
+	}
+}
+
+func TestBindBody_UnsupportedMediaType(t *testing.T) {
+	r := New()
+	r.Use(ErrorHandler())
+	r.POST("/users", WrapE(func(c *Context) error {
+		return c.Bind(&bindUser{})
+	}))
+	for contentType, code := range map[string]int{
+		"text/plain":                   http.StatusUnsupportedMediaType,
+		"application/xml":              http.StatusUnsupportedMediaType,
+		"application/merge-patch+json": http.StatusOK,
+	} {
+		req := httptest.NewRequest("POST", "/users", strings.NewReader(`{"name": "tom"}`))
+		req.Header.Set("Content-Type", contentType)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		if w.Code != code {
+			t.Fatalf("%s: expect %d, but got %d %s", contentType, code, w.Code, w.Body.String())
+		}
+	}
+}
+
+type badRuleUser struct {
+	Name string `json:"name" validate:"required,slug"`
+}
+
+type badKindUser struct {
+	Admin bool `json:"admin" validate:"min=1"`
+}
+
+func TestValidate_BadTags(t *testing.T) {
+	if err := Validate(&badRuleUser{Name: "tom"}); err == nil || !strings.Contains(err.Error(), "unknown rule slug") {
+		t.Fatalf("expect an unknown rule error, but got %v", err)
+	}
+	if err := Validate(&badKindUser{Admin: true}); err == nil || !strings.Contains(err.Error(), "min does not apply to bool") {
+		t.Fatalf("expect a bad kind error, but got %v", err)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expect Typed to panic on bad validate tags when the handler is registered")
+		}
+	}()
+	Typed(func(c *Context, req badKindUser) (H, error) { return nil, nil })
+}
@@ -0,0 +1,61 @@
+package tinyGin
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// ErrBodyTooLarge is returned by the body of requests over the MaxBodySize limit,
+// ErrorHandler and Typed answer it with 413
+var ErrBodyTooLarge = errors.New("request body too large")
+
+// MaxBodySize limits the request body to limit bytes with http.MaxBytesReader.
+// A request with a larger Content-Length is answered 413 at once, otherwise reading
+// past the limit fails with ErrBodyTooLarge and 413 is written if the handlers did not respond
+func MaxBodySize(limit int64) HandlerFunc {
+	return func(c *Context) {
+		if c.Req.ContentLength > limit {
+			c.Abort()
+			c.Problem(bodyTooLarge(limit))
+			return
+		}
+		if c.Req.Body == nil || c.Req.Body == http.NoBody {
+			c.Next()
+			return
+		}
+		body := &limitedBody{ReadCloser: http.MaxBytesReader(c.Writer, c.Req.Body, limit), limit: limit}
+		c.Req.Body = body
+		c.Next()
+		if body.exceeded && c.StatusCode == 0 {
+			c.Problem(bodyTooLarge(limit))
+		}
+	}
+}
+
+func bodyTooLarge(limit int64) *HTTPError {
+	return &HTTPError{
+		Status: http.StatusRequestEntityTooLarge,
+		Detail: fmt.Sprintf("the request body is limited to %d bytes", limit),
+		Err:    ErrBodyTooLarge,
+	}
+}
+
+// limitedBody turns the error of http.MaxBytesReader into ErrBodyTooLarge
+type limitedBody struct {
+	io.ReadCloser
+	limit    int64
+	read     int64
+	exceeded bool
+}
+
+func (b *limitedBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.read += int64(n)
+	if err != nil && err != io.EOF && b.read >= b.limit {
+		b.exceeded = true
+		err = ErrBodyTooLarge
+	}
+	return n, err
+}
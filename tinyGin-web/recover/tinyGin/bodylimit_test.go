@@ -0,0 +1,98 @@
+package tinyGin
+
+import (
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMaxBodySize(t *testing.T) {
+	r := New()
+	r.Use(ErrorHandler(), MaxBodySize(8))
+	r.POST("/raw", func(c *Context) {
+		if _, err := io.ReadAll(c.Req.Body); err != nil {
+			return
+		}
+		c.String(http.StatusOK, "ok")
+	})
+	r.POST("/ndjson", func(c *Context) {
+		reader := c.NDJSONReader()
+		var values []interface{}
+		for {
+			var v interface{}
+			err := reader.Read(&v)
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				if !errors.Is(err, ErrBodyTooLarge) || len(values) > 0 {
+					t.Errorf("expect ErrBodyTooLarge before the cut line is decoded, but got %v after %v", err, values)
+				}
+				c.Error(err)
+				return
+			}
+			values = append(values, v)
+		}
+		c.JSON(http.StatusOK, values)
+	})
+	r.POST("/json", Typed(func(c *Context, req map[string]string) (map[string]string, error) {
+		return req, nil
+	}))
+
+	cases := []struct {
+		target string
+		body   string
+		length int64 // -1 for a chunked body
+		code   int
+	}{
+		{"/raw", "12345678", 8, http.StatusOK},
+		{"/raw", "123456789", 9, http.StatusRequestEntityTooLarge},
+		{"/raw", "123456789", -1, http.StatusRequestEntityTooLarge},
+		{"/ndjson", "1\n2\n", -1, http.StatusOK},
+		{"/ndjson", "1234567890123\n", -1, http.StatusRequestEntityTooLarge},
+		{"/json", `{"a":"b"}`, -1, http.StatusRequestEntityTooLarge},
+		{"/json", `{"a":1}`, -1, http.StatusBadRequest},
+	}
+	for _, tc := range cases {
+		req := httptest.NewRequest("POST", tc.target, strings.NewReader(tc.body))
+		req.ContentLength = tc.length
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		if w.Code != tc.code {
+			t.Fatalf("%s %q: expect %d, but got %d %s", tc.target, tc.body, tc.code, w.Code, w.Body.String())
+		}
+	}
+}
+
+func TestEngine_NewServer(t *testing.T) {
+	r := New()
+	r.Server.ReadHeaderTimeout = 50 * time.Millisecond
+	r.GET("/", func(c *Context) { c.String(http.StatusOK, "ok") })
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := r.NewServer(ln.Addr().String())
+	go server.Serve(ln)
+	defer server.Close()
+
+	// a slow client never finishes its headers
+	conn, err := net.Dial("tcp", ln.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	_, _ = conn.Write([]byte("GET / HTTP/1.1\r\nHost: example.com\r\n"))
+	_ = conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	start := time.Now()
+	_, _ = io.ReadAll(conn)
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("expect the slow connection to be closed, but it stayed open for %v", elapsed)
+	}
+}
@@ -0,0 +1,353 @@
+package cache
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"tinyGin"
+)
+
+// Options configures a Cache
+type Options struct {
+	Store Store         // default NewMemoryStore(1024)
+	TTL   time.Duration // default 1 minute, a max-age of the response Cache-Control takes precedence
+	// Key identifies the response within its route, default the host and the request URI.
+	// The request headers named by the Vary header of the response are added to it
+	Key func(c *tinyGin.Context) string
+	// IgnoreCookie caches the requests with a Cookie header like the anonymous ones,
+	// by default they are per user as the requests with an Authorization header
+	IgnoreCookie bool
+}
+
+// Cache stores the responses of GET requests and answers the conditional requests
+type Cache struct {
+	store Store
+	ttl   time.Duration
+	key   func(c *tinyGin.Context) string
+
+	ignoreCookie bool
+}
+
+// New is the constructor of Cache
+func New(opts Options) *Cache {
+	if opts.Store == nil {
+		opts.Store = NewMemoryStore(1024)
+	}
+	if opts.TTL <= 0 {
+		opts.TTL = time.Minute
+	}
+	if opts.Key == nil {
+		opts.Key = func(c *tinyGin.Context) string {
+			return c.Req.Host + c.Req.URL.RequestURI()
+		}
+	}
+	return &Cache{store: opts.Store, ttl: opts.TTL, key: opts.Key, ignoreCookie: opts.IgnoreCookie}
+}
+
+// Key returns the key of the request in the store, the route pattern and the Key option
+// separated by a space, e.g. "/users/:id example.com/users/7?fields=name"
+func (cache *Cache) Key(c *tinyGin.Context) string {
+	return c.FullPath() + " " + cache.key(c)
+}
+
+// Invalidate removes the entries whose key starts with prefix, e.g. "/users/:id " removes
+// every cached user, and returns the count
+func (cache *Cache) Invalidate(prefix string) int {
+	return cache.store.DeletePrefix(prefix)
+}
+
+// Middleware serves GET and HEAD requests from the cache, the other requests pass through.
+// A request with Cache-Control no-cache skips the cached entry, no-store skips the cache,
+// max-age limits the age of the entry and only-if-cached answers 504 on a miss.
+// Only 200 responses without no-store or private are stored. The responses to requests
+// with credentials are served and stored only with Cache-Control public
+func (cache *Cache) Middleware() tinyGin.HandlerFunc {
+	return func(c *tinyGin.Context) {
+		if c.Method != http.MethodGet && c.Method != http.MethodHead {
+			c.Next()
+			return
+		}
+		directives := parseCacheControl(c.Req.Header.Get("Cache-Control"))
+		if _, ok := directives["no-store"]; ok {
+			c.Next()
+			return
+		}
+
+		key := cache.Key(c)
+		credentialed := cache.credentialed(c.Req)
+		if _, ok := directives["no-cache"]; !ok {
+			if entry, ok := cache.lookup(key, c.Req); ok && fresh(entry, directives) && (!credentialed || public(entry.Header)) {
+				c.Abort()
+				writeEntry(c, entry, "HIT")
+				return
+			}
+		}
+		if _, ok := directives["only-if-cached"]; ok {
+			c.Abort()
+			c.Status(http.StatusGatewayTimeout)
+			return
+		}
+
+		w := &recorder{ResponseWriter: c.Writer, status: http.StatusOK}
+		c.Writer = w
+		c.Next()
+		c.Writer = w.ResponseWriter
+		if w.streamed {
+			return
+		}
+
+		entry, ttl, ok := cache.entry(w, credentialed)
+		if !ok {
+			// not cacheable, the buffered response is written as is
+			c.Status(w.status)
+			c.Writer.Write(w.body.Bytes())
+			return
+		}
+		if c.Method == http.MethodGet {
+			if len(entry.Vary) > 0 {
+				// the entry at key only tells which headers the response varies on
+				cache.store.Set(key, &Entry{Vary: entry.Vary}, ttl)
+				key = varyKey(key, entry.Vary, c.Req)
+				entry.Vary = nil
+			}
+			cache.store.Set(key, entry, ttl)
+		}
+		writeEntry(c, entry, "MISS")
+	}
+}
+
+// credentialed reports whether the request carries credentials, its response may be
+// for this user only
+func (cache *Cache) credentialed(req *http.Request) bool {
+	if req.Header.Get("Authorization") != "" {
+		return true
+	}
+	return !cache.ignoreCookie && req.Header.Get("Cookie") != ""
+}
+
+// public reports whether the response allows a shared cache to store it for any user
+func public(header http.Header) bool {
+	_, ok := parseCacheControl(header.Get("Cache-Control"))["public"]
+	return ok
+}
+
+// lookup returns the entry of key, or of its variant matching the request headers
+func (cache *Cache) lookup(key string, req *http.Request) (*Entry, bool) {
+	entry, ok := cache.store.Get(key)
+	if ok && len(entry.Vary) > 0 {
+		entry, ok = cache.store.Get(varyKey(key, entry.Vary, req))
+	}
+	return entry, ok
+}
+
+// varyKey appends the values of the varying request headers to key
+func varyKey(key string, vary []string, req *http.Request) string {
+	var b strings.Builder
+	b.WriteString(key)
+	for _, name := range vary {
+		b.WriteString("\n" + name + ": " + strings.Join(req.Header.Values(name), ", "))
+	}
+	return b.String()
+}
+
+// parseVary returns the canonical header names of Vary, false if the response
+// varies per client, on cookies or credentials, and must not be shared
+func parseVary(header http.Header) ([]string, bool) {
+	var names []string
+	for _, value := range header.Values("Vary") {
+		for _, name := range strings.Split(value, ",") {
+			name = http.CanonicalHeaderKey(strings.TrimSpace(name))
+			switch name {
+			case "":
+				continue
+			case "*", "Cookie", "Authorization":
+				return nil, false
+			}
+			names = append(names, name)
+		}
+	}
+	return names, true
+}
+
+// entry converts the recorded response into an Entry with its ttl.
+// Responses setting cookies are not stored, they belong to a single client,
+// nor the responses to requests with credentials unless they are public
+func (cache *Cache) entry(w *recorder, credentialed bool) (*Entry, time.Duration, bool) {
+	if w.status != http.StatusOK {
+		return nil, 0, false
+	}
+	header := w.Header()
+	if len(header.Values("Set-Cookie")) > 0 {
+		return nil, 0, false
+	}
+	vary, ok := parseVary(header)
+	if !ok {
+		return nil, 0, false
+	}
+	directives := parseCacheControl(header.Get("Cache-Control"))
+	if _, ok := directives["no-store"]; ok {
+		return nil, 0, false
+	}
+	if _, ok := directives["private"]; ok {
+		return nil, 0, false
+	}
+	if _, ok := directives["public"]; credentialed && !ok {
+		return nil, 0, false
+	}
+	ttl := cache.ttl
+	if maxAge, ok := directives["max-age"]; ok {
+		seconds, err := strconv.Atoi(maxAge)
+		if err != nil || seconds <= 0 {
+			return nil, 0, false
+		}
+		ttl = time.Duration(seconds) * time.Second
+	}
+
+	now := time.Now()
+	entry := &Entry{
+		Status:       w.status,
+		Header:       header.Clone(),
+		Body:         w.body.Bytes(),
+		ETag:         header.Get("ETag"),
+		LastModified: now.UTC().Truncate(time.Second),
+		Stored:       now,
+		Vary:         vary,
+	}
+	if entry.ETag == "" {
+		sum := sha256.Sum256(entry.Body)
+		entry.ETag = `"` + hex.EncodeToString(sum[:16]) + `"`
+	}
+	if t, err := http.ParseTime(header.Get("Last-Modified")); err == nil {
+		entry.LastModified = t
+	}
+	entry.Header.Del("ETag")
+	entry.Header.Del("Last-Modified")
+	return entry, ttl, true
+}
+
+// fresh reports if entry satisfies the max-age directive of the request
+func fresh(entry *Entry, directives map[string]string) bool {
+	maxAge, ok := directives["max-age"]
+	if !ok {
+		return true
+	}
+	seconds, err := strconv.Atoi(maxAge)
+	return err == nil && time.Since(entry.Stored) <= time.Duration(seconds)*time.Second
+}
+
+func writeEntry(c *tinyGin.Context, entry *Entry, status string) {
+	header := c.Writer.Header()
+	for name, values := range entry.Header {
+		header[name] = values
+	}
+	header.Set("ETag", entry.ETag)
+	header.Set("Last-Modified", entry.LastModified.UTC().Format(http.TimeFormat))
+	header.Set("Age", strconv.Itoa(int(time.Since(entry.Stored).Seconds())))
+	header.Set("X-Cache", status)
+
+	if notModified(c.Req, entry) {
+		header.Del("Content-Type")
+		header.Del("Content-Length")
+		c.Status(http.StatusNotModified)
+		return
+	}
+	c.Status(entry.Status)
+	if c.Method != http.MethodHead {
+		c.Writer.Write(entry.Body)
+	}
+}
+
+// notModified evaluates If-None-Match, or else If-Modified-Since
+// refer https://www.rfc-editor.org/rfc/rfc9110#section-13.2.2
+func notModified(req *http.Request, entry *Entry) bool {
+	if inm := req.Header.Get("If-None-Match"); inm != "" {
+		for _, tag := range strings.Split(inm, ",") {
+			tag = strings.TrimSpace(tag)
+			// weak comparison
+			if tag == "*" || strings.TrimPrefix(tag, "W/") == strings.TrimPrefix(entry.ETag, "W/") {
+				return true
+			}
+		}
+		return false
+	}
+	if ims := req.Header.Get("If-Modified-Since"); ims != "" {
+		t, err := http.ParseTime(ims)
+		return err == nil && !entry.LastModified.Truncate(time.Second).After(t)
+	}
+	return false
+}
+
+// parseCacheControl returns the directives with their values, lower cased
+func parseCacheControl(value string) map[string]string {
+	directives := make(map[string]string)
+	for _, part := range strings.Split(value, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		name, arg := part, ""
+		if i := strings.IndexByte(part, '='); i >= 0 {
+			name, arg = part[:i], strings.Trim(part[i+1:], `"`)
+		}
+		directives[strings.ToLower(name)] = arg
+	}
+	return directives
+}
+
+// recorder buffers the response of the handlers. Once they flush or hijack the
+// connection, e.g. for server-sent events or WebSocket, the response is passed
+// through and not cached
+type recorder struct {
+	http.ResponseWriter
+	status   int
+	body     bytes.Buffer
+	streamed bool
+}
+
+func (w *recorder) WriteHeader(code int) {
+	if w.streamed {
+		w.ResponseWriter.WriteHeader(code)
+		return
+	}
+	w.status = code
+}
+
+func (w *recorder) Write(b []byte) (int, error) {
+	if w.streamed {
+		return w.ResponseWriter.Write(b)
+	}
+	return w.body.Write(b)
+}
+
+func (w *recorder) Flush() {
+	if !w.streamed {
+		w.streamed = true
+		w.ResponseWriter.WriteHeader(w.status)
+		w.ResponseWriter.Write(w.body.Bytes())
+		w.body.Reset()
+	}
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+func (w *recorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	h, ok := w.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, errors.New("cache: response does not implement http.Hijacker")
+	}
+	w.streamed = true
+	return h.Hijack()
+}
+
+func (w *recorder) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
@@ -0,0 +1,216 @@
+package cache
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"tinyGin"
+)
+
+func newTestEngine(cache *Cache, calls *int) *tinyGin.Engine {
+	r := tinyGin.New()
+	r.Use(cache.Middleware())
+	r.GET("/users/:id", func(c *tinyGin.Context) {
+		*calls++
+		c.JSON(http.StatusOK, tinyGin.H{"id": c.Param("id")})
+	})
+	r.GET("/private", func(c *tinyGin.Context) {
+		*calls++
+		c.SetHeader("Cache-Control", "private")
+		c.String(http.StatusOK, "secret")
+	})
+	return r
+}
+
+func do(r http.Handler, target string, header ...string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest("GET", target, nil)
+	for i := 0; i+1 < len(header); i += 2 {
+		req.Header.Set(header[i], header[i+1])
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestCache_Middleware(t *testing.T) {
+	calls := 0
+	cache := New(Options{})
+	r := newTestEngine(cache, &calls)
+
+	first := do(r, "/users/1")
+	etag := first.Header().Get("ETag")
+	if first.Code != http.StatusOK || first.Header().Get("X-Cache") != "MISS" || etag == "" {
+		t.Fatalf("expect a 200 miss with an ETag, but got %d %v", first.Code, first.Header())
+	}
+	second := do(r, "/users/1")
+	if second.Header().Get("X-Cache") != "HIT" || second.Body.String() != first.Body.String() ||
+		second.Header().Get("Content-Type") != "application/json" || calls != 1 {
+		t.Fatalf("expect a hit with the same body, but got %v %q after %d calls", second.Header(), second.Body.String(), calls)
+	}
+	if w := do(r, "/users/2"); w.Header().Get("X-Cache") != "MISS" || calls != 2 {
+		t.Fatalf("expect another key to miss, but got %s", w.Header().Get("X-Cache"))
+	}
+
+	if w := do(r, "/users/1", "If-None-Match", `"other", `+etag); w.Code != http.StatusNotModified || w.Body.Len() != 0 {
+		t.Fatalf("expect 304 for a matching ETag, but got %d", w.Code)
+	}
+	if w := do(r, "/users/1", "If-None-Match", `"other"`); w.Code != http.StatusOK {
+		t.Fatalf("expect 200 for another ETag, but got %d", w.Code)
+	}
+	lastModified := first.Header().Get("Last-Modified")
+	if w := do(r, "/users/1", "If-Modified-Since", lastModified); w.Code != http.StatusNotModified {
+		t.Fatalf("expect 304 for If-Modified-Since, but got %d", w.Code)
+	}
+	past := time.Now().Add(-time.Hour).UTC().Format(http.TimeFormat)
+	if w := do(r, "/users/1", "If-Modified-Since", past); w.Code != http.StatusOK {
+		t.Fatalf("expect 200 when modified since, but got %d", w.Code)
+	}
+
+	if w := do(r, "/users/1", "Cache-Control", "no-cache"); w.Header().Get("X-Cache") != "MISS" || calls != 3 {
+		t.Fatalf("expect no-cache to revalidate, but got %s after %d calls", w.Header().Get("X-Cache"), calls)
+	}
+	if w := do(r, "/users/3", "Cache-Control", "no-store"); w.Header().Get("X-Cache") != "" || calls != 4 {
+		t.Fatal("expect no-store to bypass the cache")
+	}
+	if w := do(r, "/users/3", "Cache-Control", "only-if-cached"); w.Code != http.StatusGatewayTimeout {
+		t.Fatalf("expect 504 for only-if-cached, but got %d", w.Code)
+	}
+	do(r, "/private")
+	if w := do(r, "/private"); w.Header().Get("X-Cache") != "" || w.Body.String() != "secret" || calls != 6 {
+		t.Fatalf("expect a private response not to be stored, but got %s after %d calls", w.Header().Get("X-Cache"), calls)
+	}
+
+	if n := cache.Invalidate("/users/:id "); n != 2 {
+		t.Fatalf("expect 2 entries invalidated, but got %d", n)
+	}
+	if w := do(r, "/users/1"); w.Header().Get("X-Cache") != "MISS" {
+		t.Fatal("expect a miss after the invalidation")
+	}
+}
+
+func TestCache_Shared(t *testing.T) {
+	calls := 0
+	r := tinyGin.New()
+	r.Use(New(Options{}).Middleware())
+	r.GET("/tenant", func(c *tinyGin.Context) {
+		calls++
+		c.String(http.StatusOK, "%s", c.Req.Host)
+	})
+	r.GET("/login", func(c *tinyGin.Context) {
+		calls++
+		http.SetCookie(c.Writer, &http.Cookie{Name: "_csrf", Value: strconv.Itoa(calls)})
+		c.String(http.StatusOK, "form")
+	})
+	r.GET("/lang", func(c *tinyGin.Context) {
+		calls++
+		c.SetHeader("Vary", "Accept-Language")
+		c.String(http.StatusOK, "%s", c.Req.Header.Get("Accept-Language"))
+	})
+	r.GET("/me", func(c *tinyGin.Context) {
+		calls++
+		c.SetHeader("Vary", "Cookie")
+		c.String(http.StatusOK, "me")
+	})
+	r.GET("/events", func(c *tinyGin.Context) {
+		calls++
+		c.String(http.StatusOK, "data: tick\n\n")
+		c.Writer.(http.Flusher).Flush()
+	})
+
+	do(r, "http://a.t.com/tenant")
+	if w := do(r, "http://b.t.com/tenant"); w.Body.String() != "b.t.com" || w.Header().Get("X-Cache") != "MISS" {
+		t.Fatalf("expect another host to miss, but got %q %s", w.Body.String(), w.Header().Get("X-Cache"))
+	}
+
+	do(r, "/login")
+	if w := do(r, "/login"); w.Header().Get("X-Cache") != "" || w.Header().Get("Set-Cookie") != "_csrf=4" {
+		t.Fatalf("expect a response setting cookies not to be stored, but got %v", w.Header())
+	}
+
+	do(r, "/lang", "Accept-Language", "en")
+	if w := do(r, "/lang", "Accept-Language", "fr"); w.Body.String() != "fr" || w.Header().Get("X-Cache") != "MISS" {
+		t.Fatalf("expect another language to miss, but got %q %s", w.Body.String(), w.Header().Get("X-Cache"))
+	}
+	if w := do(r, "/lang", "Accept-Language", "en"); w.Body.String() != "en" || w.Header().Get("X-Cache") != "HIT" {
+		t.Fatalf("expect the same language to hit, but got %q %s", w.Body.String(), w.Header().Get("X-Cache"))
+	}
+
+	do(r, "/me")
+	if w := do(r, "/me"); w.Header().Get("X-Cache") != "" {
+		t.Fatal("expect a response varying on cookies not to be stored")
+	}
+
+	calls = 0
+	do(r, "/events")
+	if w := do(r, "/events"); w.Body.String() != "data: tick\n\n" || !w.Flushed || calls != 2 {
+		t.Fatalf("expect a flushed response to pass through, but got %q after %d calls", w.Body.String(), calls)
+	}
+}
+
+func TestCache_Credentials(t *testing.T) {
+	calls := 0
+	handler := func(c *tinyGin.Context) {
+		calls++
+		user := c.Req.Header.Get("Authorization")
+		if cookie, err := c.Req.Cookie("user"); err == nil {
+			user = cookie.Value
+		}
+		c.String(http.StatusOK, "hello %s", user)
+	}
+	r := tinyGin.New()
+	r.Use(New(Options{}).Middleware())
+	r.GET("/me", handler)
+	r.GET("/news", func(c *tinyGin.Context) {
+		c.SetHeader("Cache-Control", "public")
+		handler(c)
+	})
+
+	do(r, "/me", "Authorization", "alice")
+	if w := do(r, "/me", "Authorization", "bob"); w.Body.String() != "hello bob" || w.Header().Get("X-Cache") != "" {
+		t.Fatalf("expect bob not to get the response of alice, but got %q %s", w.Body.String(), w.Header().Get("X-Cache"))
+	}
+	do(r, "/me", "Cookie", "user=alice")
+	if w := do(r, "/me", "Cookie", "user=bob"); w.Body.String() != "hello bob" || w.Header().Get("X-Cache") != "" {
+		t.Fatalf("expect cookies to be per user, but got %q %s", w.Body.String(), w.Header().Get("X-Cache"))
+	}
+	do(r, "/me")
+	if w := do(r, "/me", "Authorization", "bob"); w.Body.String() != "hello bob" || calls != 6 {
+		t.Fatalf("expect an anonymous entry not to be served with credentials, but got %q", w.Body.String())
+	}
+
+	do(r, "/news", "Authorization", "alice")
+	if w := do(r, "/news", "Authorization", "bob"); w.Body.String() != "hello alice" || w.Header().Get("X-Cache") != "HIT" {
+		t.Fatalf("expect a public response to be shared, but got %q %s", w.Body.String(), w.Header().Get("X-Cache"))
+	}
+
+	r = tinyGin.New()
+	r.Use(New(Options{IgnoreCookie: true}).Middleware())
+	r.GET("/me", handler)
+	do(r, "/me", "Cookie", "user=alice")
+	if w := do(r, "/me", "Cookie", "user=bob"); w.Header().Get("X-Cache") != "HIT" {
+		t.Fatalf("expect IgnoreCookie to share the response, but got %s", w.Header().Get("X-Cache"))
+	}
+}
+
+func TestMemoryStore(t *testing.T) {
+	store := NewMemoryStore(2)
+	store.Set("a", &Entry{}, 0)
+	store.Set("b", &Entry{}, 0)
+	store.Get("a")
+	store.Set("c", &Entry{}, 0)
+	if _, ok := store.Get("b"); ok {
+		t.Fatal("expect the least recently used entry to be evicted")
+	}
+	if _, ok := store.Get("a"); !ok || store.Len() != 2 {
+		t.Fatalf("expect a to be kept, len %d", store.Len())
+	}
+
+	store.Set("d", &Entry{}, time.Millisecond)
+	time.Sleep(5 * time.Millisecond)
+	if _, ok := store.Get("d"); ok {
+		t.Fatal("expect an expired entry to be missing")
+	}
+}
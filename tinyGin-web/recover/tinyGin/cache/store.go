@@ -0,0 +1,115 @@
+package cache
+
+import (
+	"container/list"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Entry is a cached response
+type Entry struct {
+	Status       int
+	Header       http.Header
+	Body         []byte
+	ETag         string
+	LastModified time.Time
+	Stored       time.Time
+	// Vary are the request headers the response varies on, an entry with Vary
+	// only refers to the entries of its variants
+	Vary []string
+}
+
+// Store keeps the cached responses
+type Store interface {
+	// Get returns the entry of key, false if it is missing or expired
+	Get(key string) (*Entry, bool)
+	// Set stores the entry of key for ttl, 0 means no expiry
+	Set(key string, entry *Entry, ttl time.Duration)
+	// DeletePrefix removes the entries whose key starts with prefix and returns the count
+	DeletePrefix(prefix string) int
+}
+
+type memoryItem struct {
+	key     string
+	entry   *Entry
+	expires time.Time
+}
+
+// MemoryStore is a LRU Store in memory
+type MemoryStore struct {
+	mu       sync.Mutex
+	capacity int
+	ll       *list.List
+	items    map[string]*list.Element
+}
+
+// NewMemoryStore is the constructor of MemoryStore, the least recently used entries
+// are evicted beyond capacity entries, capacity <= 0 means no limit
+func NewMemoryStore(capacity int) *MemoryStore {
+	return &MemoryStore{
+		capacity: capacity,
+		ll:       list.New(),
+		items:    make(map[string]*list.Element),
+	}
+}
+
+func (store *MemoryStore) Get(key string) (*Entry, bool) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	ele, ok := store.items[key]
+	if !ok {
+		return nil, false
+	}
+	item := ele.Value.(*memoryItem)
+	if !item.expires.IsZero() && time.Now().After(item.expires) {
+		store.remove(ele)
+		return nil, false
+	}
+	store.ll.MoveToFront(ele)
+	return item.entry, true
+}
+
+func (store *MemoryStore) Set(key string, entry *Entry, ttl time.Duration) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	item := &memoryItem{key: key, entry: entry}
+	if ttl > 0 {
+		item.expires = time.Now().Add(ttl)
+	}
+	if ele, ok := store.items[key]; ok {
+		ele.Value = item
+		store.ll.MoveToFront(ele)
+		return
+	}
+	store.items[key] = store.ll.PushFront(item)
+	for store.capacity > 0 && store.ll.Len() > store.capacity {
+		store.remove(store.ll.Back())
+	}
+}
+
+func (store *MemoryStore) DeletePrefix(prefix string) int {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	n := 0
+	for key, ele := range store.items {
+		if strings.HasPrefix(key, prefix) {
+			store.remove(ele)
+			n++
+		}
+	}
+	return n
+}
+
+// Len returns the number of entries, including the expired ones not yet removed
+func (store *MemoryStore) Len() int {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	return store.ll.Len()
+}
+
+func (store *MemoryStore) remove(ele *list.Element) {
+	store.ll.Remove(ele)
+	delete(store.items, ele.Value.(*memoryItem).key)
+}
@@ -0,0 +1,46 @@
+package tinyGin
+
+import (
+	"net"
+	"strings"
+)
+
+// ClientIP returns the IP of the client. X-Forwarded-For is used only when the request
+// comes from one of engine.TrustedProxies, the trusted hops are skipped from the right
+func (c *Context) ClientIP() string {
+	remote := c.Req.RemoteAddr
+	if host, _, err := net.SplitHostPort(remote); err == nil {
+		remote = host
+	}
+	if c.engine == nil || !c.engine.isTrustedProxy(remote) {
+		return remote
+	}
+	hops := strings.Split(c.Req.Header.Get("X-Forwarded-For"), ",")
+	for i := len(hops) - 1; i >= 0; i-- {
+		hop := strings.TrimSpace(hops[i])
+		if net.ParseIP(hop) == nil {
+			break
+		}
+		if i == 0 || !c.engine.isTrustedProxy(hop) {
+			return hop
+		}
+	}
+	return remote
+}
+
+func (engine *Engine) isTrustedProxy(addr string) bool {
+	ip := net.ParseIP(addr)
+	if ip == nil {
+		return false
+	}
+	for _, proxy := range engine.TrustedProxies {
+		if strings.Contains(proxy, "/") {
+			if _, network, err := net.ParseCIDR(proxy); err == nil && network.Contains(ip) {
+				return true
+			}
+		} else if trusted := net.ParseIP(proxy); trusted != nil && trusted.Equal(ip) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,87 @@
+package tinyGin
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// paramTypes are the named constraints of route params, e.g. :id<int>
+var paramTypes = struct {
+	sync.RWMutex
+	checks map[string]func(string) bool
+}{
+	checks: map[string]func(string) bool{
+		"int": func(s string) bool {
+			_, err := strconv.ParseInt(s, 10, 64)
+			return err == nil
+		},
+		"uint": func(s string) bool {
+			_, err := strconv.ParseUint(s, 10, 64)
+			return err == nil
+		},
+		"float": func(s string) bool {
+			_, err := strconv.ParseFloat(s, 64)
+			return err == nil
+		},
+		"alpha": regexp.MustCompile(`^[a-zA-Z]+$`).MatchString,
+		"uuid":  regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`).MatchString,
+	},
+}
+
+// RegisterParamType adds a named constraint usable as :param<name>, it must be called before routes are added
+func RegisterParamType(name string, check func(string) bool) {
+	paramTypes.Lock()
+	defer paramTypes.Unlock()
+	paramTypes.checks[name] = check
+}
+
+// splitParam splits a part such as :id<int> into the param name and the constraint
+func splitParam(part string) (name string, constraint string) {
+	name = part[1:]
+	if i := strings.IndexByte(name, '<'); i >= 0 && strings.HasSuffix(name, ">") {
+		return name[:i], name[i+1 : len(name)-1]
+	}
+	return name, ""
+}
+
+// paramCheck returns the check of the constraint of part, nil if part has no constraint.
+// A constraint is a registered param type or else a regular expression matching the whole segment
+func paramCheck(part string) func(string) bool {
+	if part[0] != ':' {
+		return nil
+	}
+	_, constraint := splitParam(part)
+	if constraint == "" {
+		return nil
+	}
+
+	paramTypes.RLock()
+	check, ok := paramTypes.checks[constraint]
+	paramTypes.RUnlock()
+	if ok {
+		return check
+	}
+	re, err := regexp.Compile("^(?:" + constraint + ")$")
+	if err != nil {
+		panic(fmt.Sprintf("tinyGin: bad constraint of route param %s: %v", part, err))
+	}
+	return re.MatchString
+}
+
+// ParamInt returns the param converted to int, use it with the :param<int> constraint
+func (c *Context) ParamInt(key string) (int, error) {
+	return strconv.Atoi(c.Param(key))
+}
+
+// ParamInt64 returns the param converted to int64
+func (c *Context) ParamInt64(key string) (int64, error) {
+	return strconv.ParseInt(c.Param(key), 10, 64)
+}
+
+// ParamFloat returns the param converted to float64, use it with the :param<float> constraint
+func (c *Context) ParamFloat(key string) (float64, error) {
+	return strconv.ParseFloat(c.Param(key), 64)
+}
@@ -1,9 +1,11 @@
 package tinyGin
 
 import (
-	"encoding/json"
+	"bytes"
 	"fmt"
 	"net/http"
+
+	"tinyGin/internal/hooks"
 )
 
 type H map[string]interface{}
@@ -16,11 +18,18 @@ type Context struct {
 	Path   string
 	Method string
 	Params map[string]string
+	// matched route pattern
+	fullPath string
+	route    *Route
 	// response info
 	StatusCode int
 	// middleware
 	handlers []HandlerFunc
 	index    int
+	// key/value pairs shared by the handlers of this request
+	Keys map[string]interface{}
+	// errors recorded by the handlers
+	Errors []error
 	// engine pointer
 	engine *Engine
 }
@@ -35,6 +44,15 @@ func newContext(w http.ResponseWriter, req *http.Request) *Context {
 	}
 }
 
+func init() {
+	hooks.NewTestContext = func(w http.ResponseWriter, req *http.Request) (interface{}, interface{}) {
+		engine := New()
+		c := newContext(w, req)
+		c.engine = engine
+		return c, engine
+	}
+}
+
 func (c *Context) Next() {
 	c.index++
 	s := len(c.handlers)
@@ -43,16 +61,54 @@ func (c *Context) Next() {
 	}
 }
 
-func (c *Context) Fail(code int, err string) {
+// Abort skips the remaining handlers, the handlers that already called Next still finish
+func (c *Context) Abort() {
 	c.index = len(c.handlers)
+}
+
+func (c *Context) Fail(code int, err string) {
+	c.Abort()
 	c.JSON(code, H{"message": err})
 }
 
+// Set stores a value for this request only
+func (c *Context) Set(key string, value interface{}) {
+	if c.Keys == nil {
+		c.Keys = make(map[string]interface{})
+	}
+	c.Keys[key] = value
+}
+
+// Get returns the value stored by Set
+func (c *Context) Get(key string) (value interface{}, exists bool) {
+	value, exists = c.Keys[key]
+	return
+}
+
+// MustGet returns the value stored by Set, it panics if the key does not exist
+func (c *Context) MustGet(key string) interface{} {
+	if value, exists := c.Get(key); exists {
+		return value
+	}
+	panic("Key \"" + key + "\" does not exist")
+}
+
 func (c *Context) Param(key string) string {
 	value, _ := c.Params[key]
 	return value
 }
 
+// FullPath returns the matched route pattern, e.g. /hello/:name,
+// or an empty string when no route matched
+func (c *Context) FullPath() string {
+	return c.fullPath
+}
+
+// Route returns the matched route with its metadata, nil when no route matched
+func (c *Context) Route() *Route {
+	return c.route
+}
+
 func (c *Context) PostForm(key string) string {
 	return c.Req.FormValue(key)
 }
@@ -79,12 +135,17 @@ func (c *Context) String(code int, format string, values ...interface{}) {
 func (c *Context) JSON(code int, obj interface{}) {
 	c.SetHeader("Content-Type", "application/json")
 	c.Status(code)
-	encoder := json.NewEncoder(c.Writer)
-	if err := encoder.Encode(obj); err != nil {
+	if err := c.jsonCodec().NewEncoder(c.Writer).Encode(obj); err != nil {
 		http.Error(c.Writer, err.Error(), 500)
 	}
 }
 
+// Redirect replies with a redirect to location, e.g. 303 after a form POST
+func (c *Context) Redirect(code int, location string) {
+	c.StatusCode = code
+	http.Redirect(c.Writer, c.Req, location, code)
+}
+
 func (c *Context) Data(code int, data []byte) {
 	c.Status(code)
 	c.Writer.Write(data)
@@ -93,9 +154,12 @@ func (c *Context) Data(code int, data []byte) {
 // HTML template render
 // refer https://golang.org/pkg/html/template/
 func (c *Context) HTML(code int, name string, data interface{}) {
-	c.SetHeader("Content-Type", "text/html")
-	c.Status(code)
-	if err := c.engine.htmlTemplates.ExecuteTemplate(c.Writer, name, data); err != nil {
+	var buf bytes.Buffer
+	if err := c.engine.renderHTML(&buf, name, data); err != nil {
 		c.Fail(500, err.Error())
+		return
 	}
+	c.SetHeader("Content-Type", "text/html")
+	c.Status(code)
+	c.Writer.Write(buf.Bytes())
 }
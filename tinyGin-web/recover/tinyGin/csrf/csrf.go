@@ -0,0 +1,197 @@
+package csrf
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+	"html/template"
+	"io"
+	"net/http"
+
+	"tinyGin"
+	"tinyGin/sessions"
+)
+
+const (
+	tokenKey   = "tinyGin/csrf"
+	tokenBytes = 32
+)
+
+// Pattern decides where the real token of a client is kept
+type Pattern int
+
+const (
+	// DoubleSubmit keeps the token in a cookie, the form field or header must repeat it
+	DoubleSubmit Pattern = iota
+	// Synchronizer keeps the token in the session, the Sessions middleware must run first
+	Synchronizer
+)
+
+// Options configures the CSRF middleware, zero values fall back to the defaults
+type Options struct {
+	Pattern      Pattern
+	CookieName   string // for DoubleSubmit, default "_csrf"
+	FieldName    string // form field, default "_csrf"
+	HeaderName   string // default "X-CSRF-Token"
+	Secure       bool   // the Secure attribute of the cookie
+	ErrorHandler tinyGin.HandlerFunc
+}
+
+var safeMethods = map[string]bool{
+	http.MethodGet:     true,
+	http.MethodHead:    true,
+	http.MethodOptions: true,
+	http.MethodTrace:   true,
+}
+
+// New returns the CSRF middleware, every unsafe request must carry a valid token
+func New(opts Options) tinyGin.HandlerFunc {
+	if opts.CookieName == "" {
+		opts.CookieName = "_csrf"
+	}
+	if opts.FieldName == "" {
+		opts.FieldName = "_csrf"
+	}
+	if opts.HeaderName == "" {
+		opts.HeaderName = "X-CSRF-Token"
+	}
+	if opts.ErrorHandler == nil {
+		opts.ErrorHandler = func(c *tinyGin.Context) {
+			c.Fail(http.StatusForbidden, "CSRF token mismatch")
+		}
+	}
+
+	return func(c *tinyGin.Context) {
+		token, err := opts.load(c)
+		if err != nil {
+			c.Fail(http.StatusInternalServerError, err.Error())
+			return
+		}
+		masked, err := mask(token)
+		if err != nil {
+			c.Fail(http.StatusInternalServerError, err.Error())
+			return
+		}
+		c.Set(tokenKey, &state{masked: masked, field: opts.FieldName})
+
+		if !safeMethods[c.Method] {
+			sent := c.Req.Header.Get(opts.HeaderName)
+			if sent == "" {
+				sent = c.PostForm(opts.FieldName)
+			}
+			if !valid(token, sent) {
+				opts.ErrorHandler(c)
+				return
+			}
+		}
+		c.Next()
+	}
+}
+
+// load returns the real token of the client, a new one is issued if there is none
+func (opts *Options) load(c *tinyGin.Context) ([]byte, error) {
+	if opts.Pattern == Synchronizer {
+		s := sessions.Default(c)
+		if token, ok := s.Get(tokenKey).([]byte); ok && len(token) == tokenBytes {
+			return token, nil
+		}
+		token, err := newToken()
+		if err != nil {
+			return nil, err
+		}
+		s.Set(tokenKey, token)
+		return token, s.Save()
+	}
+
+	if cookie, err := c.Req.Cookie(opts.CookieName); err == nil {
+		token, err := base64.RawURLEncoding.DecodeString(cookie.Value)
+		if err == nil && len(token) == tokenBytes {
+			return token, nil
+		}
+	}
+	token, err := newToken()
+	if err != nil {
+		return nil, err
+	}
+	http.SetCookie(c.Writer, &http.Cookie{
+		Name:     opts.CookieName,
+		Value:    base64.RawURLEncoding.EncodeToString(token),
+		Path:     "/",
+		MaxAge:   86400 * 30,
+		Secure:   opts.Secure,
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	})
+	return token, nil
+}
+
+func newToken() ([]byte, error) {
+	token := make([]byte, tokenBytes)
+	if _, err := io.ReadFull(rand.Reader, token); err != nil {
+		return nil, err
+	}
+	return token, nil
+}
+
+// mask xors the token with a one-time pad, so the rendered token differs on every response
+// refer https://en.wikipedia.org/wiki/BREACH
+func mask(token []byte) (string, error) {
+	pad := make([]byte, tokenBytes)
+	if _, err := io.ReadFull(rand.Reader, pad); err != nil {
+		return "", err
+	}
+	masked := make([]byte, 2*tokenBytes)
+	copy(masked, pad)
+	for i := range token {
+		masked[tokenBytes+i] = pad[i] ^ token[i]
+	}
+	return base64.RawURLEncoding.EncodeToString(masked), nil
+}
+
+func valid(expected []byte, sent string) bool {
+	masked, err := base64.RawURLEncoding.DecodeString(sent)
+	if err != nil || len(masked) != 2*tokenBytes {
+		return false
+	}
+	token := make([]byte, tokenBytes)
+	for i := range token {
+		token[i] = masked[i] ^ masked[tokenBytes+i]
+	}
+	return subtle.ConstantTimeCompare(expected, token) == 1
+}
+
+// state is stored on the Context for Token and TemplateField, the token is masked
+// once per request by the middleware
+type state struct {
+	masked string
+	field  string
+}
+
+// Token returns a masked token of the current request for forms or headers
+func Token(c *tinyGin.Context) string {
+	value, ok := c.Get(tokenKey)
+	if !ok {
+		return ""
+	}
+	return value.(*state).masked
+}
+
+// TemplateField returns a hidden input holding the token of the current request
+func TemplateField(c *tinyGin.Context) template.HTML {
+	value, ok := c.Get(tokenKey)
+	if !ok {
+		return ""
+	}
+	st := value.(*state)
+	return template.HTML(fmt.Sprintf(`<input type="hidden" name="%s" value="%s">`,
+		template.HTMLEscapeString(st.field), st.masked))
+}
+
+// FuncMap returns the csrfField template func for Engine.SetFuncMap, use it as {{ csrfField .ctx }}
+// where ctx is the *tinyGin.Context of the request
+func FuncMap() template.FuncMap {
+	return template.FuncMap{
+		"csrfField": TemplateField,
+	}
+}
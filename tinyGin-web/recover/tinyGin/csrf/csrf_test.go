@@ -0,0 +1,88 @@
+package csrf
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"tinyGin"
+	"tinyGin/sessions"
+)
+
+func newTestEngine(middlewares ...tinyGin.HandlerFunc) *tinyGin.Engine {
+	r := tinyGin.New()
+	r.Use(middlewares...)
+	r.GET("/form", func(c *tinyGin.Context) {
+		c.String(http.StatusOK, "%s", Token(c))
+	})
+	r.POST("/form", func(c *tinyGin.Context) {
+		c.String(http.StatusOK, "ok")
+	})
+	return r
+}
+
+// issue returns the cookies and token handed out by GET /form
+func issue(r *tinyGin.Engine) ([]*http.Cookie, string) {
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/form", nil))
+	return w.Result().Cookies(), w.Body.String()
+}
+
+func post(r *tinyGin.Engine, cookies []*http.Cookie, form url.Values, header string) int {
+	req := httptest.NewRequest("POST", "/form", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	if header != "" {
+		req.Header.Set("X-CSRF-Token", header)
+	}
+	for _, cookie := range cookies {
+		req.AddCookie(cookie)
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w.Code
+}
+
+func TestDoubleSubmit(t *testing.T) {
+	r := newTestEngine(New(Options{}))
+	cookies, token := issue(r)
+
+	if code := post(r, cookies, nil, ""); code != http.StatusForbidden {
+		t.Fatalf("expect 403 without token, but got %d", code)
+	}
+	if code := post(r, nil, url.Values{"_csrf": {token}}, ""); code != http.StatusForbidden {
+		t.Fatalf("expect 403 without cookie, but got %d", code)
+	}
+	if code := post(r, cookies, url.Values{"_csrf": {token}}, ""); code != http.StatusOK {
+		t.Fatalf("expect 200 with form field, but got %d", code)
+	}
+	if code := post(r, cookies, nil, token); code != http.StatusOK {
+		t.Fatalf("expect 200 with header, but got %d", code)
+	}
+}
+
+func TestSynchronizer(t *testing.T) {
+	store, _ := sessions.NewMemoryStore([]byte("0123456789abcdef"))
+	r := newTestEngine(sessions.Sessions("tiny", store), New(Options{Pattern: Synchronizer}))
+	cookies, token := issue(r)
+
+	if code := post(r, cookies, url.Values{"_csrf": {"forged"}}, ""); code != http.StatusForbidden {
+		t.Fatalf("expect 403 with forged token, but got %d", code)
+	}
+	if code := post(r, cookies, url.Values{"_csrf": {token}}, ""); code != http.StatusOK {
+		t.Fatalf("expect 200 with form field, but got %d", code)
+	}
+}
+
+func TestTemplateField(t *testing.T) {
+	r := newTestEngine(New(Options{FieldName: "token"}))
+	r.GET("/field", func(c *tinyGin.Context) {
+		c.Data(http.StatusOK, []byte(TemplateField(c)))
+	})
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/field", nil))
+	if !strings.HasPrefix(w.Body.String(), `<input type="hidden" name="token" value="`) {
+		t.Fatalf("unexpected field %s", w.Body.String())
+	}
+}
@@ -0,0 +1,92 @@
+// Package dbtx runs each request of a group in a tinyGorm transaction.
+// It is a module of its own, so tinyGin does not depend on tinyGorm
+package dbtx
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"tinyGin"
+	"tinyGorm"
+	"tinyGorm/session"
+)
+
+const txKey = "tinyGin/dbtx"
+
+// ErrNoTransaction is returned by Commit without the middleware
+var ErrNoTransaction = errors.New("dbtx: no transaction")
+
+// tx is the transaction of a request, done once committed or rolled back
+type tx struct {
+	s    *session.Session
+	done bool
+}
+
+// New returns the middleware beginning a transaction per request, the handlers use it by Session(c).
+// The transaction is committed when the chain ends with a status below 400 and no error recorded
+// by c.Error, and rolled back otherwise or when a handler panics, the panic is then re-raised
+// for Recovery, which must run before this middleware.
+//
+// The handlers usually write the response before the middleware commits, so a failed commit
+// can not change the status the client got, it is only recorded by c.Error. A handler which
+// must report it calls Commit before rendering, the middleware then leaves the transaction alone
+func New(engine *tinyGorm.Engine) tinyGin.HandlerFunc {
+	return func(c *tinyGin.Context) {
+		s := engine.NewSession()
+		if err := s.Begin(); err != nil {
+			c.Error(err)
+			c.Fail(http.StatusServiceUnavailable, "database unavailable")
+			return
+		}
+		t := &tx{s: s}
+		c.Set(txKey, t)
+
+		defer func() {
+			if !t.done {
+				// a handler panicked
+				t.done = true
+				_ = s.Rollback()
+			}
+		}()
+		c.Next()
+		if t.done {
+			return
+		}
+
+		t.done = true
+		if c.StatusCode >= http.StatusBadRequest || len(c.Errors) > 0 {
+			_ = s.Rollback()
+			return
+		}
+		if err := s.Commit(); err != nil {
+			c.Error(fmt.Errorf("dbtx: commit: %w", err))
+		}
+	}
+}
+
+// Session returns the session of the transaction of the request, nil without the middleware
+func Session(c *tinyGin.Context) *session.Session {
+	t, _ := c.Keys[txKey].(*tx)
+	if t == nil {
+		return nil
+	}
+	return t.s
+}
+
+// Commit commits the transaction of the request now, so a handler can answer an error
+// when it fails. It is a no-op if the transaction is already committed or rolled back
+func Commit(c *tinyGin.Context) error {
+	t, _ := c.Keys[txKey].(*tx)
+	if t == nil {
+		return ErrNoTransaction
+	}
+	if t.done {
+		return nil
+	}
+	t.done = true
+	if err := t.s.Commit(); err != nil {
+		return fmt.Errorf("dbtx: commit: %w", err)
+	}
+	return nil
+}
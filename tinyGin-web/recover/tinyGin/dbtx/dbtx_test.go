@@ -0,0 +1,95 @@
+package dbtx
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"tinyGin"
+	"tinyGorm"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+type User struct {
+	Name string `tinyOrm:"PRIMARY KEY"`
+	Age  int
+}
+
+func TestNew(t *testing.T) {
+	tinyGin.SetMode(tinyGin.TestMode)
+	db, err := tinyGorm.NewEngine("sqlite3", filepath.Join(t.TempDir(), "dbtx.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	if err := db.NewSession().Model(&User{}).CreateTable(); err != nil {
+		t.Fatal(err)
+	}
+
+	r := tinyGin.New()
+	r.Use(tinyGin.Recovery())
+	api := r.Group("/api")
+	api.Use(func(c *tinyGin.Context) {
+		c.Next()
+		if c.Req.URL.Path == "/api/early/ann" && len(c.Errors) > 0 {
+			t.Errorf("expect the middleware to leave a committed transaction alone, but got %v", c.Errors)
+		}
+	})
+	api.Use(New(db))
+	insert := func(c *tinyGin.Context) {
+		if _, err := Session(c).Model(&User{}).Insert(&User{Name: c.Param("name"), Age: 18}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	api.POST("/ok/:name", insert, func(c *tinyGin.Context) { c.Status(http.StatusCreated) })
+	api.POST("/status/:name", insert, func(c *tinyGin.Context) { c.Status(http.StatusConflict) })
+	api.POST("/error/:name", insert, func(c *tinyGin.Context) {
+		c.Error(errors.New("audit failed"))
+		c.Status(http.StatusCreated)
+	})
+	api.POST("/panic/:name", insert, func(c *tinyGin.Context) { panic("boom") })
+	api.POST("/early/:name", insert, func(c *tinyGin.Context) {
+		if err := Commit(c); err != nil {
+			c.Fail(http.StatusInternalServerError, err.Error())
+			return
+		}
+		c.Status(http.StatusCreated)
+	})
+	r.POST("/outside", func(c *tinyGin.Context) {
+		if Session(c) != nil || Commit(c) != ErrNoTransaction {
+			c.Status(http.StatusInternalServerError)
+			return
+		}
+		c.Status(http.StatusNoContent)
+	})
+
+	for target, code := range map[string]int{
+		"/api/ok/tom":      http.StatusCreated,
+		"/api/status/jack": http.StatusConflict,
+		"/api/error/sam":   http.StatusCreated,
+		"/api/panic/sue":   http.StatusInternalServerError,
+		"/api/early/ann":   http.StatusCreated,
+		"/outside":         http.StatusNoContent,
+	} {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest("POST", target, nil))
+		if w.Code != code {
+			t.Fatalf("%s: expect %d, but got %d", target, code, w.Code)
+		}
+	}
+	var users []User
+	if err := db.NewSession().Model(&User{}).Find(&users); err != nil {
+		t.Fatal(err)
+	}
+	if len(users) != 2 {
+		t.Fatalf("expect only tom and ann to be committed, but got %v", users)
+	}
+	for _, u := range users {
+		if u.Name != "tom" && u.Name != "ann" {
+			t.Fatalf("expect only tom and ann to be committed, but got %v", users)
+		}
+	}
+}
@@ -0,0 +1,14 @@
+module tinyGin/dbtx
+
+go 1.18
+
+replace (
+	tinyGin => ../
+	tinyGorm => ../../../../tinyGorm/transaction
+)
+
+require (
+	github.com/mattn/go-sqlite3 v1.14.16
+	tinyGin v0.0.0
+	tinyGorm v0.0.0
+)
@@ -0,0 +1,133 @@
+package tinyGin
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/pprof"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Checker reports if a dependency is ready, it should return soon after ctx is done
+type Checker func(ctx context.Context) error
+
+type checker struct {
+	check   Checker
+	timeout time.Duration
+}
+
+// Diagnostics serves the probes registered by EnableDiagnostics
+type Diagnostics struct {
+	engine *Engine
+	// Timeout is used by the checkers added without one
+	Timeout time.Duration
+
+	mu       sync.RWMutex
+	checkers map[string]checker
+}
+
+// EnableDiagnostics registers GET /healthz and /readyz on the engine, and the net/http/pprof
+// handlers under group + /debug/pprof/, group should be protected by an auth middleware.
+// The pprof handlers are not registered if group is nil
+func (engine *Engine) EnableDiagnostics(group *RouterGroup) *Diagnostics {
+	d := &Diagnostics{engine: engine, Timeout: 2 * time.Second, checkers: make(map[string]checker)}
+	engine.GET("/healthz", d.healthz)
+	engine.GET("/readyz", d.readyz)
+	if group != nil {
+		group.GET("/debug/pprof/", WrapF(pprof.Index))
+		group.GET("/debug/pprof/cmdline", WrapF(pprof.Cmdline))
+		group.GET("/debug/pprof/profile", WrapF(pprof.Profile))
+		group.GET("/debug/pprof/symbol", WrapF(pprof.Symbol))
+		group.POST("/debug/pprof/symbol", WrapF(pprof.Symbol))
+		group.GET("/debug/pprof/trace", WrapF(pprof.Trace))
+		// pprof.Index serves the named profiles only under /debug/pprof/
+		group.GET("/debug/pprof/:name", func(c *Context) {
+			WrapH(pprof.Handler(c.Param("name")))(c)
+		})
+	}
+	return d
+}
+
+// AddChecker adds a checker aggregated by /readyz, timeout 0 means d.Timeout
+func (d *Diagnostics) AddChecker(name string, timeout time.Duration, check Checker) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.checkers[name] = checker{check: check, timeout: timeout}
+}
+
+// healthz reports the process is alive, it never calls the checkers
+func (d *Diagnostics) healthz(c *Context) {
+	c.JSON(http.StatusOK, H{"status": "ok"})
+}
+
+// readyz runs the checkers concurrently, it fails if any checker fails or times out,
+// or once the engine is shutting down
+func (d *Diagnostics) readyz(c *Context) {
+	if d.engine.ShuttingDown() {
+		c.JSON(http.StatusServiceUnavailable, H{"status": "shutting down"})
+		return
+	}
+
+	d.mu.RLock()
+	names := make([]string, 0, len(d.checkers))
+	for name := range d.checkers {
+		names = append(names, name)
+	}
+	checkers := make([]checker, len(names))
+	sort.Strings(names)
+	for i, name := range names {
+		checkers[i] = d.checkers[name]
+	}
+	d.mu.RUnlock()
+
+	results := make([]string, len(names))
+	var wg sync.WaitGroup
+	for i := range checkers {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i] = d.run(c.Req.Context(), checkers[i])
+		}(i)
+	}
+	wg.Wait()
+
+	status, code := "ok", http.StatusOK
+	checks := make(map[string]string, len(names))
+	for i, name := range names {
+		checks[name] = results[i]
+		if results[i] != "ok" {
+			status, code = "unavailable", http.StatusServiceUnavailable
+		}
+	}
+	c.JSON(code, H{"status": status, "checks": checks})
+}
+
+func (d *Diagnostics) run(parent context.Context, ch checker) (result string) {
+	timeout := ch.timeout
+	if timeout <= 0 {
+		timeout = d.Timeout
+	}
+	ctx, cancel := context.WithTimeout(parent, timeout)
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() {
+		defer func() {
+			if err := recover(); err != nil {
+				done <- fmt.Errorf("panic: %v", err)
+			}
+		}()
+		done <- ch.check(ctx)
+	}()
+	select {
+	case err := <-done:
+		if err != nil {
+			return err.Error()
+		}
+		return "ok"
+	case <-ctx.Done():
+		return "timeout after " + timeout.String()
+	}
+}
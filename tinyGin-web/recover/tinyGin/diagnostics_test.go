@@ -0,0 +1,72 @@
+package tinyGin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestEngine_EnableDiagnostics(t *testing.T) {
+	r := New()
+	admin := r.Group("/admin")
+	admin.Use(func(c *Context) {
+		if c.Query("token") != "secret" {
+			c.Fail(http.StatusUnauthorized, "unauthorized")
+		}
+	})
+	d := r.EnableDiagnostics(admin)
+	d.AddChecker("db", 0, func(ctx context.Context) error { return nil })
+
+	if w := serve(r, "GET", "/healthz"); w.Code != http.StatusOK {
+		t.Fatalf("expect 200 from healthz, but got %d", w.Code)
+	}
+	if w := serve(r, "GET", "/readyz"); w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `"db":"ok"`) {
+		t.Fatalf("expect db ok, but got %d %s", w.Code, w.Body.String())
+	}
+
+	d.AddChecker("cache", 0, func(ctx context.Context) error { return errors.New("connection refused") })
+	d.AddChecker("queue", 10*time.Millisecond, func(ctx context.Context) error {
+		<-ctx.Done()
+		time.Sleep(10 * time.Millisecond)
+		return nil
+	})
+	w := serve(r, "GET", "/readyz")
+	var body struct {
+		Status string
+		Checks map[string]string
+	}
+	_ = json.Unmarshal(w.Body.Bytes(), &body)
+	if w.Code != http.StatusServiceUnavailable || body.Status != "unavailable" || body.Checks["db"] != "ok" ||
+		body.Checks["cache"] != "connection refused" || body.Checks["queue"] != "timeout after 10ms" {
+		t.Fatalf("expect cache and queue to fail, but got %d %s", w.Code, w.Body.String())
+	}
+
+	if w := serve(r, "GET", "/admin/debug/pprof/"); w.Code != http.StatusUnauthorized {
+		t.Fatalf("expect pprof to be protected, but got %d", w.Code)
+	}
+	if w := serve(r, "GET", "/admin/debug/pprof/?token=secret"); w.Code != http.StatusOK || !strings.Contains(w.Body.String(), "goroutine") {
+		t.Fatalf("expect the pprof index, but got %d", w.Code)
+	}
+	if w := serve(r, "GET", "/admin/debug/pprof/goroutine?debug=1&token=secret"); w.Code != http.StatusOK ||
+		!strings.Contains(w.Body.String(), "goroutine profile") {
+		t.Fatalf("expect the goroutine profile, but got %d", w.Code)
+	}
+}
+
+func TestEngine_Shutdown(t *testing.T) {
+	r := New()
+	r.EnableDiagnostics(nil)
+	if err := r.Shutdown(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if w := serve(r, "GET", "/readyz"); w.Code != http.StatusServiceUnavailable {
+		t.Fatalf("expect readyz to fail during shutdown, but got %d", w.Code)
+	}
+	if w := serve(r, "GET", "/healthz"); w.Code != http.StatusOK {
+		t.Fatalf("expect healthz to pass during shutdown, but got %d", w.Code)
+	}
+}
@@ -0,0 +1,162 @@
+package tinyGin
+
+import (
+	"errors"
+	"net/http"
+	"reflect"
+)
+
+// HTTPError is an error with the fields of a RFC 7807 problem
+// refer https://www.rfc-editor.org/rfc/rfc7807
+type HTTPError struct {
+	Status     int
+	Type       string                 // an URI of the problem type, default about:blank
+	Title      string                 // default http.StatusText(Status)
+	Detail     string                 // explanation of this occurrence
+	Extensions map[string]interface{} // extra members of the problem
+	Err        error                  // the wrapped error, never sent to the client
+}
+
+// NewHTTPError is the constructor of HTTPError
+func NewHTTPError(status int, detail string) *HTTPError {
+	return &HTTPError{Status: status, Detail: detail}
+}
+
+func (e *HTTPError) Error() string {
+	msg := http.StatusText(e.Status)
+	if e.Title != "" {
+		msg = e.Title
+	}
+	if e.Detail != "" {
+		msg += ": " + e.Detail
+	}
+	if e.Err != nil {
+		msg += ": " + e.Err.Error()
+	}
+	return msg
+}
+
+func (e *HTTPError) Unwrap() error {
+	return e.Err
+}
+
+// ErrorMapping maps the errors matched by Is or As to a status
+type ErrorMapping struct {
+	Is     error       // matched by errors.Is
+	As     interface{} // a value of the error type matched by errors.As, e.g. (*MyError)(nil)
+	Status int
+	Type   string
+	Title  string
+}
+
+func (m *ErrorMapping) match(err error) bool {
+	if m.Is != nil && errors.Is(err, m.Is) {
+		return true
+	}
+	if m.As != nil {
+		target := reflect.New(reflect.TypeOf(m.As))
+		return errors.As(err, target.Interface())
+	}
+	return false
+}
+
+// Error records an error of the request, ErrorHandler writes it as the response
+func (c *Context) Error(err error) {
+	c.Errors = append(c.Errors, err)
+}
+
+// WrapE adapts a handler returning an error, the error is recorded by c.Error
+// and the following handlers are skipped
+func WrapE(handler func(c *Context) error) HandlerFunc {
+	return func(c *Context) {
+		if err := handler(c); err != nil {
+			c.Error(err)
+			c.Abort()
+		}
+	}
+}
+
+const errorMappingsKey = "tinyGin/errorMappings"
+
+// ErrorHandler writes the last error recorded on the Context as application/problem+json,
+// if the handlers have not written a response. A *HTTPError keeps its own status,
+// other errors use the first matching mapping, then ErrBodyTooLarge answers 413,
+// ValidationErrors 422, a BindingError 400 and the others 500 without the error detail
+func ErrorHandler(mappings ...ErrorMapping) HandlerFunc {
+	return func(c *Context) {
+		// for the handlers writing their errors, e.g. Typed
+		c.Set(errorMappingsKey, mappings)
+		c.Next()
+		if len(c.Errors) == 0 || c.StatusCode != 0 {
+			return
+		}
+		c.Problem(toHTTPError(c.Errors[len(c.Errors)-1], mappings))
+	}
+}
+
+func toHTTPError(err error, mappings []ErrorMapping) *HTTPError {
+	var httpErr *HTTPError
+	if errors.As(err, &httpErr) {
+		return httpErr
+	}
+	for _, m := range mappings {
+		if m.match(err) {
+			return &HTTPError{Status: m.Status, Type: m.Type, Title: m.Title, Detail: err.Error(), Err: err}
+		}
+	}
+	if errors.Is(err, ErrBodyTooLarge) {
+		return &HTTPError{Status: http.StatusRequestEntityTooLarge, Detail: err.Error(), Err: err}
+	}
+	var validationErrs ValidationErrors
+	if errors.As(err, &validationErrs) {
+		return &HTTPError{
+			Status:     http.StatusUnprocessableEntity,
+			Detail:     err.Error(),
+			Extensions: map[string]interface{}{"errors": validationErrs.Map()},
+			Err:        err,
+		}
+	}
+	if errors.Is(err, ErrUnsupportedMediaType) {
+		return &HTTPError{Status: http.StatusUnsupportedMediaType, Detail: err.Error(), Err: err}
+	}
+	var bindingErr *BindingError
+	if errors.As(err, &bindingErr) {
+		return &HTTPError{Status: http.StatusBadRequest, Detail: err.Error(), Err: err}
+	}
+	return &HTTPError{Status: http.StatusInternalServerError, Err: err}
+}
+
+// writeError records err and writes it as a problem, with the mappings of ErrorHandler if it runs
+func (c *Context) writeError(err error) {
+	c.Error(err)
+	c.Abort()
+	mappings, _ := c.Keys[errorMappingsKey].([]ErrorMapping)
+	c.Problem(toHTTPError(err, mappings))
+}
+
+// Problem writes err as application/problem+json
+func (c *Context) Problem(err *HTTPError) {
+	problem := make(map[string]interface{}, len(err.Extensions)+5)
+	for key, value := range err.Extensions {
+		problem[key] = value
+	}
+	problem["type"] = "about:blank"
+	if err.Type != "" {
+		problem["type"] = err.Type
+	}
+	problem["title"] = http.StatusText(err.Status)
+	if err.Title != "" {
+		problem["title"] = err.Title
+	}
+	problem["status"] = err.Status
+	if err.Detail != "" {
+		problem["detail"] = err.Detail
+	}
+	problem["instance"] = c.Req.URL.Path
+
+	c.SetHeader("Content-Type", "application/problem+json")
+	c.Status(err.Status)
+	if err := c.jsonCodec().NewEncoder(c.Writer).Encode(problem); err != nil {
+		http.Error(c.Writer, err.Error(), 500)
+	}
+}
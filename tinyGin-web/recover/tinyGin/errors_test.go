@@ -0,0 +1,71 @@
+package tinyGin
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+var errNotFound = errors.New("record not found")
+
+type quotaError struct {
+	limit int
+}
+
+func (e *quotaError) Error() string {
+	return fmt.Sprintf("quota of %d exceeded", e.limit)
+}
+
+func TestErrorHandler(t *testing.T) {
+	r := New()
+	r.Use(ErrorHandler(
+		ErrorMapping{Is: errNotFound, Status: http.StatusNotFound},
+		ErrorMapping{As: (*quotaError)(nil), Status: http.StatusTooManyRequests, Type: "https://example.com/quota"},
+	))
+	r.GET("/http", WrapE(func(c *Context) error {
+		err := NewHTTPError(http.StatusBadRequest, "name is required")
+		err.Extensions = map[string]interface{}{"field": "name"}
+		return err
+	}))
+	r.GET("/is", WrapE(func(c *Context) error {
+		return fmt.Errorf("load user: %w", errNotFound)
+	}))
+	r.GET("/as", func(c *Context) {
+		c.Error(fmt.Errorf("upload: %w", &quotaError{limit: 10}))
+	})
+	r.GET("/internal", WrapE(func(c *Context) error {
+		return errors.New("db password is wrong")
+	}))
+	r.GET("/written", func(c *Context) {
+		c.Error(errors.New("ignored"))
+		c.String(http.StatusOK, "ok")
+	})
+
+	cases := []struct {
+		path    string
+		status  int
+		problem map[string]interface{}
+	}{
+		{"/http", 400, H{"type": "about:blank", "title": "Bad Request", "status": 400.0, "detail": "name is required", "instance": "/http", "field": "name"}},
+		{"/is", 404, H{"type": "about:blank", "title": "Not Found", "status": 404.0, "detail": "load user: record not found", "instance": "/is"}},
+		{"/as", 429, H{"type": "https://example.com/quota", "title": "Too Many Requests", "status": 429.0, "detail": "upload: quota of 10 exceeded", "instance": "/as"}},
+		{"/internal", 500, H{"type": "about:blank", "title": "Internal Server Error", "status": 500.0, "instance": "/internal"}},
+	}
+	for _, tc := range cases {
+		w := serve(r, "GET", tc.path)
+		if w.Code != tc.status || w.Header().Get("Content-Type") != "application/problem+json" {
+			t.Fatalf("%s: expect %d problem+json, but got %d %s", tc.path, tc.status, w.Code, w.Header().Get("Content-Type"))
+		}
+		var problem map[string]interface{}
+		_ = json.Unmarshal(w.Body.Bytes(), &problem)
+		if fmt.Sprint(problem) != fmt.Sprint(tc.problem) {
+			t.Fatalf("%s: expect %v, but got %v", tc.path, tc.problem, problem)
+		}
+	}
+
+	if w := serve(r, "GET", "/written"); w.Code != http.StatusOK || w.Body.String() != "ok" {
+		t.Fatalf("expect the written response to be kept, but got %d %q", w.Code, w.Body.String())
+	}
+}
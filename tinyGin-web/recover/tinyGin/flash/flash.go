@@ -0,0 +1,257 @@
+package flash
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"tinyGin"
+)
+
+const stateKey = "tinyGin/flash"
+
+// Options configures the flash middleware, zero values fall back to the defaults
+type Options struct {
+	CookieName string        // default "_flash"
+	Path       string        // default "/"
+	MaxAge     time.Duration // how long a flash waits for the next request, default 5 minutes
+	Secure     bool          // the Secure attribute of the cookie
+}
+
+// Message is a flash message, Kind is e.g. success or error
+type Message struct {
+	Kind string `json:"k"`
+	Text string `json:"t"`
+}
+
+// maxCookieSize is the size of the name and value of a cookie browsers keep at least
+const maxCookieSize = 4096
+
+// data is what a request leaves to the next one, it must fit in a cookie of maxCookieSize
+type data struct {
+	Messages []Message         `json:"m,omitempty"`
+	Input    url.Values        `json:"i,omitempty"`
+	Errors   map[string]string `json:"e,omitempty"`
+}
+
+func (d *data) empty() bool {
+	return len(d.Messages) == 0 && len(d.Input) == 0 && len(d.Errors) == 0
+}
+
+// state holds the data left by the previous request and the data for the next one
+type state struct {
+	opts    *Options
+	hashKey []byte
+	current data
+	next    data
+	loaded  bool // a flash cookie came with the request
+}
+
+// New returns the flash middleware, hashKey signs the cookie with HMAC-SHA256.
+// The data left by the previous request is read once, the cookie is then removed
+func New(hashKey []byte, opts Options) tinyGin.HandlerFunc {
+	if len(hashKey) == 0 {
+		panic("flash: hash key is required")
+	}
+	if opts.CookieName == "" {
+		opts.CookieName = "_flash"
+	}
+	if opts.Path == "" {
+		opts.Path = "/"
+	}
+	if opts.MaxAge <= 0 {
+		opts.MaxAge = 5 * time.Minute
+	}
+	return func(c *tinyGin.Context) {
+		st := &state{opts: &opts, hashKey: hashKey}
+		if cookie, err := c.Req.Cookie(opts.CookieName); err == nil {
+			st.loaded = true
+			_ = st.decode(cookie.Value, &st.current)
+			// consumed, unless this request leaves a flash too
+			st.save(c)
+		}
+		c.Set(stateKey, st)
+		c.Next()
+	}
+}
+
+func load(c *tinyGin.Context) *state {
+	st, ok := c.Keys[stateKey].(*state)
+	if !ok {
+		panic("flash: the flash middleware is not used")
+	}
+	return st
+}
+
+// Add leaves a message to the next request
+func Add(c *tinyGin.Context, kind string, text string) {
+	st := load(c)
+	st.next.Messages = append(st.next.Messages, Message{Kind: kind, Text: text})
+	st.save(c)
+}
+
+// Messages returns the messages left by the previous request
+func Messages(c *tinyGin.Context) []Message {
+	return load(c).current.Messages
+}
+
+// WithInput keeps the submitted form and the field errors of err, for this request and the
+// next one, so the form can be rendered again now or after a redirect.
+// ValidationErrors give one error per field, keyed by the form name of the field, or else its
+// json name. A BindingError of a field gives its field, other errors become an error message.
+// Password fields and fields starting with _ are not kept
+func WithInput(c *tinyGin.Context, err error) {
+	st := load(c)
+	_ = c.Req.ParseForm()
+	input := make(url.Values)
+	for name, values := range c.Req.PostForm {
+		if strings.HasPrefix(name, "_") || strings.Contains(strings.ToLower(name), "password") {
+			continue
+		}
+		input[name] = values
+	}
+	fieldErrors := make(map[string]string)
+	var validationErrs tinyGin.ValidationErrors
+	var bindingErr *tinyGin.BindingError
+	switch {
+	case err == nil:
+	case errors.As(err, &validationErrs):
+		for _, fieldErr := range validationErrs {
+			key := fieldErr.Form
+			if key == "" {
+				key = fieldErr.Field
+			}
+			if _, ok := fieldErrors[key]; !ok {
+				fieldErrors[key] = fieldErr.Message
+			}
+		}
+	case errors.As(err, &bindingErr) && bindingErr.Field != "":
+		fieldErrors[bindingErr.Field] = bindingErr.Err.Error()
+	default:
+		st.next.Messages = append(st.next.Messages, Message{Kind: "error", Text: err.Error()})
+	}
+
+	st.next.Input, st.next.Errors = input, fieldErrors
+	st.current.Input, st.current.Errors = input, fieldErrors
+	st.save(c)
+}
+
+// Back keeps the input and errors like WithInput and redirects to location with 303
+func Back(c *tinyGin.Context, location string, err error) {
+	WithInput(c, err)
+	c.Redirect(http.StatusSeeOther, location)
+}
+
+// save replaces the flash cookie of the response with the next data, or removes it
+func (st *state) save(c *tinyGin.Context) {
+	header := c.Writer.Header()
+	cookies := header.Values("Set-Cookie")
+	header.Del("Set-Cookie")
+	for _, cookie := range cookies {
+		if !strings.HasPrefix(cookie, st.opts.CookieName+"=") {
+			header.Add("Set-Cookie", cookie)
+		}
+	}
+
+	cookie := &http.Cookie{
+		Name:     st.opts.CookieName,
+		Path:     st.opts.Path,
+		Secure:   st.opts.Secure,
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	}
+	if st.next.empty() {
+		if !st.loaded {
+			return
+		}
+		cookie.MaxAge = -1
+	} else {
+		value, err := st.fit(st.next)
+		if err != nil {
+			return
+		}
+		cookie.Value = value
+		cookie.MaxAge = int(st.opts.MaxAge / time.Second)
+	}
+	http.SetCookie(c.Writer, cookie)
+}
+
+// fit encodes d for the cookie. Beyond maxCookieSize, which browsers would silently drop,
+// the largest input values are dropped first, then the field errors and the oldest messages
+func (st *state) fit(d data) (string, error) {
+	if len(d.Input) > 0 {
+		input := make(url.Values, len(d.Input))
+		for name, values := range d.Input {
+			input[name] = values
+		}
+		d.Input = input
+	}
+	for {
+		value, err := st.encode(&d)
+		if err != nil {
+			return "", err
+		}
+		if len(st.opts.CookieName)+1+len(value) <= maxCookieSize {
+			return value, nil
+		}
+		switch {
+		case len(d.Input) > 0:
+			largest, size := "", -1
+			for name, values := range d.Input {
+				if n := len(name) + len(strings.Join(values, "")); n > size {
+					largest, size = name, n
+				}
+			}
+			delete(d.Input, largest)
+		case len(d.Errors) > 0:
+			d.Errors = nil
+		case len(d.Messages) > 0:
+			d.Messages = d.Messages[1:]
+		default:
+			return "", errors.New("flash: cookie too large")
+		}
+	}
+}
+
+// encode signs d as "timestamp.payload.mac", the cookie name is part of the mac
+func (st *state) encode(d *data) (string, error) {
+	payload, err := json.Marshal(d)
+	if err != nil {
+		return "", err
+	}
+	value := strconv.FormatInt(time.Now().Unix(), 10) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	return value + "." + st.mac(value), nil
+}
+
+func (st *state) decode(value string, d *data) error {
+	i := strings.LastIndexByte(value, '.')
+	if i < 0 || !hmac.Equal([]byte(value[i+1:]), []byte(st.mac(value[:i]))) {
+		return errors.New("flash: invalid signature")
+	}
+	parts := strings.SplitN(value[:i], ".", 2)
+	if len(parts) != 2 {
+		return errors.New("flash: invalid value")
+	}
+	ts, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil || time.Since(time.Unix(ts, 0)) > st.opts.MaxAge {
+		return errors.New("flash: expired")
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(payload, d)
+}
+
+func (st *state) mac(value string) string {
+	h := hmac.New(sha256.New, st.hashKey)
+	h.Write([]byte(st.opts.CookieName + "|" + value))
+	return base64.RawURLEncoding.EncodeToString(h.Sum(nil))
+}
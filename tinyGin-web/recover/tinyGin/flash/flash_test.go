@@ -0,0 +1,138 @@
+package flash
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"tinyGin"
+)
+
+type signup struct {
+	Name     string `json:"name" form:"name" validate:"required,min=3"`
+	Email    string `json:"email" form:"email" validate:"required,email"`
+	Password string `json:"password" form:"password" validate:"required"`
+}
+
+func newTestEngine() *tinyGin.Engine {
+	tinyGin.SetMode(tinyGin.TestMode)
+	r := tinyGin.New()
+	r.SetFuncMap(FuncMap())
+	r.LoadHTMLFS(fstest.MapFS{
+		"signup.tmpl": {Data: []byte(`{{ range flashes .ctx }}[{{ .Kind }}: {{ .Text }}]{{ end }}` +
+			`{{ formInput .ctx "text" "name" }}|{{ old .ctx "email" }}|{{ fieldError .ctx "email" }}|{{ formInput .ctx "password" "password" }}`)},
+	}, "*.tmpl")
+	r.Use(New([]byte("0123456789abcdef0123456789abcdef"), Options{}))
+	r.GET("/signup", func(c *tinyGin.Context) {
+		c.HTML(http.StatusOK, "signup.tmpl", tinyGin.H{"ctx": c})
+	})
+	r.POST("/signup", func(c *tinyGin.Context) {
+		var form signup
+		if err := c.Bind(&form); err != nil {
+			Back(c, "/signup", err)
+			return
+		}
+		Add(c, "success", "welcome "+form.Name)
+		c.Redirect(http.StatusSeeOther, "/signup")
+	})
+	return r
+}
+
+// follow sends req with cookies and returns the response with the cookies it sets
+func follow(r http.Handler, req *http.Request, cookies []*http.Cookie) (*httptest.ResponseRecorder, []*http.Cookie) {
+	for _, cookie := range cookies {
+		req.AddCookie(cookie)
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w, w.Result().Cookies()
+}
+
+func postForm(values url.Values) *http.Request {
+	req := httptest.NewRequest("POST", "/signup", strings.NewReader(values.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	return req
+}
+
+func TestBack(t *testing.T) {
+	r := newTestEngine()
+	w, cookies := follow(r, postForm(url.Values{"name": {"tom"}, "email": {"tom<at>example"}, "password": {"secret"}}), nil)
+	if w.Code != http.StatusSeeOther || len(cookies) != 1 || strings.Contains(cookies[0].Value, "secret") {
+		t.Fatalf("expect a redirect with the flash cookie, but got %d %v", w.Code, cookies)
+	}
+
+	w, cookies = follow(r, httptest.NewRequest("GET", "/signup", nil), cookies)
+	want := `<input type="text" name="name" value="tom">|tom&lt;at&gt;example|email must be an email address|<input type="password" name="password">`
+	if w.Body.String() != want {
+		t.Fatalf("expect\n%s\nbut got\n%s", want, w.Body.String())
+	}
+	if len(cookies) != 1 || cookies[0].MaxAge >= 0 {
+		t.Fatalf("expect the flash cookie to be removed after it is read, but got %v", cookies)
+	}
+	// the flash is shown once
+	if w, _ = follow(r, httptest.NewRequest("GET", "/signup", nil), nil); !strings.HasPrefix(w.Body.String(), `<input type="text" name="name" value="">`) {
+		t.Fatalf("expect an empty form, but got %s", w.Body.String())
+	}
+}
+
+func TestAdd(t *testing.T) {
+	r := newTestEngine()
+	_, cookies := follow(r, postForm(url.Values{"name": {"tom"}, "email": {"tom@example.com"}, "password": {"secret"}}), nil)
+	w, _ := follow(r, httptest.NewRequest("GET", "/signup", nil), cookies)
+	if !strings.HasPrefix(w.Body.String(), "[success: welcome tom]") {
+		t.Fatalf("expect the flash message, but got %s", w.Body.String())
+	}
+
+	cookies[0].Value = strings.Replace(cookies[0].Value, "t", "T", 1)
+	if w, _ = follow(r, httptest.NewRequest("GET", "/signup", nil), cookies); strings.Contains(w.Body.String(), "welcome") {
+		t.Fatal("expect a tampered cookie to be ignored")
+	}
+}
+
+type contact struct {
+	Email   string `json:"email" form:"email_address" validate:"required,email"`
+	Message string `json:"message" form:"message"`
+}
+
+func TestBack_FormNames(t *testing.T) {
+	tinyGin.SetMode(tinyGin.TestMode)
+	r := tinyGin.New()
+	r.SetFuncMap(FuncMap())
+	r.LoadHTMLFS(fstest.MapFS{
+		"contact.tmpl": {Data: []byte(`{{ formInput .ctx "email" "email_address" }}`)},
+	}, "*.tmpl")
+	r.Use(New([]byte("0123456789abcdef0123456789abcdef"), Options{}))
+	r.GET("/contact", func(c *tinyGin.Context) {
+		c.HTML(http.StatusOK, "contact.tmpl", tinyGin.H{"ctx": c})
+	})
+	r.POST("/contact", func(c *tinyGin.Context) {
+		if err := c.Bind(&contact{}); err != nil {
+			Back(c, "/contact", err)
+		}
+	})
+
+	post := func(values url.Values) []*http.Cookie {
+		req := httptest.NewRequest("POST", "/contact", strings.NewReader(values.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		_, cookies := follow(r, req, nil)
+		return cookies
+	}
+
+	w, _ := follow(r, httptest.NewRequest("GET", "/contact", nil), post(url.Values{"email_address": {"nobody"}}))
+	want := `<input type="email" name="email_address" value="nobody" aria-invalid="true"><span class="field-error">email must be an email address</span>`
+	if w.Body.String() != want {
+		t.Fatalf("expect the error under the form name\n%s\nbut got\n%s", want, w.Body.String())
+	}
+
+	// the long message does not fit in the cookie, it is dropped and the rest is kept
+	cookies := post(url.Values{"email_address": {"nobody"}, "message": {strings.Repeat("x", 8000)}})
+	if len(cookies) != 1 || len(cookies[0].Name)+1+len(cookies[0].Value) > maxCookieSize {
+		t.Fatalf("expect a flash cookie within the browser limit, but got %d cookies", len(cookies))
+	}
+	if w, _ = follow(r, httptest.NewRequest("GET", "/contact", nil), cookies); w.Body.String() != want {
+		t.Fatalf("expect the input and error to be kept\n%s\nbut got\n%s", want, w.Body.String())
+	}
+}
@@ -0,0 +1,46 @@
+package flash
+
+import (
+	"fmt"
+	"html/template"
+
+	"tinyGin"
+)
+
+// Old returns the value of a form field kept by WithInput, for repopulating the form
+func Old(c *tinyGin.Context, field string) string {
+	return load(c).current.Input.Get(field)
+}
+
+// FieldError returns the error of a form field kept by WithInput, field is the form name
+// of the bound struct field, or its json name if it has no form tag
+func FieldError(c *tinyGin.Context, field string) string {
+	return load(c).current.Errors[field]
+}
+
+// Input returns an input element with the old value, and the error of the field after it
+func Input(c *tinyGin.Context, inputType string, field string) template.HTML {
+	html := fmt.Sprintf(`<input type="%s" name="%s"`,
+		template.HTMLEscapeString(inputType), template.HTMLEscapeString(field))
+	if inputType != "password" {
+		html += fmt.Sprintf(` value="%s"`, template.HTMLEscapeString(Old(c, field)))
+	}
+	msg := FieldError(c, field)
+	if msg == "" {
+		return template.HTML(html + ">")
+	}
+	return template.HTML(fmt.Sprintf(`%s aria-invalid="true"><span class="field-error">%s</span>`,
+		html, template.HTMLEscapeString(msg)))
+}
+
+// FuncMap returns the template funcs for Engine.SetFuncMap, each takes the *tinyGin.Context
+// of the request first, e.g. {{ range flashes .ctx }}, {{ old .ctx "name" }},
+// {{ fieldError .ctx "name" }} and {{ formInput .ctx "email" "email" }}
+func FuncMap() template.FuncMap {
+	return template.FuncMap{
+		"flashes":    Messages,
+		"old":        Old,
+		"fieldError": FieldError,
+		"formInput":  Input,
+	}
+}
@@ -0,0 +1,92 @@
+package tinyGin
+
+import (
+	"net"
+	"strings"
+)
+
+// hostRouter is the router of a virtual host, with its own trie and groups
+type hostRouter struct {
+	pattern string
+	labels  []string // e.g. [* tenant example com]
+	router  *routeTable
+	groups  []*RouterGroup
+}
+
+// Host returns the root RouterGroup of requests to host, which has its own routes and middlewares.
+// The middlewares added by engine.Use apply to every host. A label of the pattern may be
+// :name to capture one label, the first label may be * to capture one or more labels as "subdomain",
+// e.g. *.tenant.example.com or :tenant.example.com
+func (engine *Engine) Host(pattern string) *RouterGroup {
+	pattern = strings.ToLower(pattern)
+	engine.mu.Lock()
+	defer engine.mu.Unlock()
+	for _, h := range engine.hosts {
+		if h.pattern == pattern {
+			return h.groups[0]
+		}
+	}
+
+	h := &hostRouter{pattern: pattern, labels: strings.Split(pattern, "."), router: newRouteTable()}
+	for i, label := range h.labels {
+		if label == "*" && i > 0 {
+			panic("tinyGin: * must be the first label of host " + pattern)
+		}
+	}
+	group := &RouterGroup{engine: engine, host: h}
+	h.groups = []*RouterGroup{group}
+	// exact hosts are matched before the patterns
+	if strings.ContainsAny(pattern, "*:") {
+		engine.hosts = append(engine.hosts, h)
+	} else {
+		engine.hosts = append([]*hostRouter{h}, engine.hosts...)
+	}
+	return group
+}
+
+// match returns the captured params if host matches h
+func (h *hostRouter) match(labels []string) (map[string]string, bool) {
+	params := make(map[string]string)
+	if h.labels[0] == "*" {
+		rest := h.labels[1:]
+		if len(labels) <= len(rest) {
+			return nil, false
+		}
+		params["subdomain"] = strings.Join(labels[:len(labels)-len(rest)], ".")
+		labels = labels[len(labels)-len(rest):]
+		return params, h.matchLabels(rest, labels, params)
+	}
+	if len(labels) != len(h.labels) {
+		return nil, false
+	}
+	return params, h.matchLabels(h.labels, labels, params)
+}
+
+func (h *hostRouter) matchLabels(patterns []string, labels []string, params map[string]string) bool {
+	for i, pattern := range patterns {
+		if strings.HasPrefix(pattern, ":") {
+			params[pattern[1:]] = labels[i]
+		} else if pattern != labels[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// matchHost returns the hostRouter of the Host header of a request, nil for the default one,
+// the caller must hold engine.mu
+func (engine *Engine) matchHost(host string) (*hostRouter, map[string]string) {
+	if len(engine.hosts) == 0 {
+		return nil, nil
+	}
+	if name, _, err := net.SplitHostPort(host); err == nil {
+		host = name
+	}
+	labels := strings.Split(strings.TrimSuffix(strings.ToLower(host), "."), ".")
+	for _, h := range engine.hosts {
+		if params, ok := h.match(labels); ok {
+			return h, params
+		}
+	}
+	return nil, nil
+}
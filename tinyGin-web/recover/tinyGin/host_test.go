@@ -0,0 +1,62 @@
+package tinyGin
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestEngine_Host(t *testing.T) {
+	r := New()
+	r.Use(func(c *Context) {
+		c.SetHeader("X-Global", "yes")
+		c.Next()
+	})
+	r.GET("/", func(c *Context) { c.String(http.StatusOK, "default") })
+
+	api := r.Host("api.example.com")
+	api.Use(func(c *Context) {
+		c.SetHeader("X-Api", "yes")
+		c.Next()
+	})
+	api.Group("/v1").GET("/users/:id", func(c *Context) {
+		c.String(http.StatusOK, "api user %s", c.Param("id"))
+	})
+	r.Host("*.tenant.example.com").GET("/", func(c *Context) {
+		c.String(http.StatusOK, "tenant %s", c.Param("subdomain"))
+	})
+	r.Host(":lang.docs.example.com").GET("/:page", func(c *Context) {
+		c.String(http.StatusOK, "docs %s %s", c.Param("lang"), c.Param("page"))
+	})
+
+	cases := []struct {
+		host string
+		path string
+		code int
+		body string
+	}{
+		{"api.example.com", "/v1/users/7", http.StatusOK, "api user 7"},
+		{"API.example.com:8080", "/v1/users/8", http.StatusOK, "api user 8"},
+		{"api.example.com", "/", http.StatusNotFound, "404 NOT FOUND: /\n"},
+		{"acme.tenant.example.com", "/", http.StatusOK, "tenant acme"},
+		{"eu.acme.tenant.example.com", "/", http.StatusOK, "tenant eu.acme"},
+		{"zh.docs.example.com", "/intro", http.StatusOK, "docs zh intro"},
+		{"tenant.example.com", "/", http.StatusOK, "default"},
+		{"other.com", "/", http.StatusOK, "default"},
+	}
+	for _, tc := range cases {
+		req := httptest.NewRequest("GET", tc.path, nil)
+		req.Host = tc.host
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		if w.Code != tc.code || w.Body.String() != tc.body {
+			t.Fatalf("%s%s: expect %d %q, but got %d %q", tc.host, tc.path, tc.code, tc.body, w.Code, w.Body.String())
+		}
+		if w.Header().Get("X-Global") != "yes" {
+			t.Fatalf("%s%s: expect the engine middleware to run", tc.host, tc.path)
+		}
+		if (w.Header().Get("X-Api") == "yes") != (tc.host[:3] == "api" || tc.host[:3] == "API") {
+			t.Fatalf("%s%s: the api middleware runs only for the api host", tc.host, tc.path)
+		}
+	}
+}
@@ -0,0 +1,148 @@
+package i18n
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// message is a plain text, or the texts of the plural categories
+type message struct {
+	text   string
+	plural map[string]string
+}
+
+var pluralCategories = map[string]bool{
+	"zero": true, "one": true, "two": true, "few": true, "many": true, "other": true,
+}
+
+// flatten converts nested tables into dotted keys, a table whose keys are all
+// plural categories is a plural message
+func flatten(prefix string, table map[string]interface{}, messages map[string]*message) error {
+	for key, value := range table {
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+		switch v := value.(type) {
+		case string:
+			messages[key] = &message{text: v}
+		case map[string]interface{}:
+			if plural, ok := pluralForms(v); ok {
+				messages[key] = &message{plural: plural}
+				continue
+			}
+			if err := flatten(key, v, messages); err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("i18n: message %s must be a string or a table, got %T", key, value)
+		}
+	}
+	return nil
+}
+
+func pluralForms(table map[string]interface{}) (map[string]string, bool) {
+	if len(table) == 0 {
+		return nil, false
+	}
+	forms := make(map[string]string, len(table))
+	for key, value := range table {
+		text, ok := value.(string)
+		if !ok || !pluralCategories[key] {
+			return nil, false
+		}
+		forms[key] = text
+	}
+	return forms, true
+}
+
+func parseJSON(data []byte) (map[string]interface{}, error) {
+	var table map[string]interface{}
+	if err := json.Unmarshal(data, &table); err != nil {
+		return nil, fmt.Errorf("i18n: %v", err)
+	}
+	return table, nil
+}
+
+// parseTOML parses the subset of TOML used by catalogs: comments, [table.headers]
+// and key = "string" pairs with dotted or quoted keys
+func parseTOML(data []byte) (map[string]interface{}, error) {
+	root := make(map[string]interface{})
+	current := root
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || line[0] == '#' {
+			continue
+		}
+		if line[0] == '[' {
+			if !strings.HasSuffix(line, "]") {
+				return nil, fmt.Errorf("i18n: line %d: bad table header %q", n, line)
+			}
+			current = root
+			for _, name := range splitKey(line[1 : len(line)-1]) {
+				current = subTable(current, name)
+			}
+			continue
+		}
+
+		i := strings.IndexByte(line, '=')
+		if i < 0 {
+			return nil, fmt.Errorf("i18n: line %d: expect key = value", n)
+		}
+		value, err := parseTOMLString(strings.TrimSpace(line[i+1:]))
+		if err != nil {
+			return nil, fmt.Errorf("i18n: line %d: %v", n, err)
+		}
+		keys := splitKey(line[:i])
+		table := current
+		for _, name := range keys[:len(keys)-1] {
+			table = subTable(table, name)
+		}
+		table[keys[len(keys)-1]] = value
+	}
+	return root, scanner.Err()
+}
+
+func splitKey(key string) []string {
+	var keys []string
+	for _, name := range strings.Split(key, ".") {
+		keys = append(keys, strings.Trim(strings.TrimSpace(name), `"`))
+	}
+	return keys
+}
+
+func subTable(table map[string]interface{}, name string) map[string]interface{} {
+	sub, ok := table[name].(map[string]interface{})
+	if !ok {
+		sub = make(map[string]interface{})
+		table[name] = sub
+	}
+	return sub
+}
+
+// parseTOMLString parses a basic "string" or a literal 'string', followed by an optional comment
+func parseTOMLString(value string) (string, error) {
+	if strings.HasPrefix(value, "'") {
+		end := strings.IndexByte(value[1:], '\'')
+		if end < 0 {
+			return "", fmt.Errorf("unterminated string %s", value)
+		}
+		return value[1 : end+1], nil
+	}
+	if !strings.HasPrefix(value, `"`) {
+		return "", fmt.Errorf("expect a string, got %s", value)
+	}
+	for end := 1; end < len(value); end++ {
+		switch value[end] {
+		case '\\':
+			end++
+		case '"':
+			return strconv.Unquote(value[:end+1])
+		}
+	}
+	return "", fmt.Errorf("unterminated string %s", value)
+}
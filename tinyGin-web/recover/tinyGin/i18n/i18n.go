@@ -0,0 +1,380 @@
+package i18n
+
+import (
+	"fmt"
+	"html/template"
+	"io/fs"
+	"net/http"
+	"os"
+	"path"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"tinyGin"
+)
+
+// Bundle holds the message catalogs of every locale
+type Bundle struct {
+	defaultLocale string
+
+	mu       sync.RWMutex
+	catalogs map[string]map[string]*message // lower cased locale -> key -> message
+	locales  []string                       // the loaded locales as named by the files
+}
+
+// NewBundle is the constructor of Bundle, defaultLocale is used when nothing matches
+// the request and for the keys missing from its locale
+func NewBundle(defaultLocale string) *Bundle {
+	return &Bundle{
+		defaultLocale: defaultLocale,
+		catalogs:      make(map[string]map[string]*message),
+	}
+}
+
+// AddMessages adds the messages of locale, a value is a string, a table of plural
+// categories such as {"one": "...", "other": "..."} or a nested table of messages
+func (b *Bundle) AddMessages(locale string, messages map[string]interface{}) error {
+	flat := make(map[string]*message)
+	if err := flatten("", messages, flat); err != nil {
+		return err
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	key := strings.ToLower(locale)
+	catalog, ok := b.catalogs[key]
+	if !ok {
+		catalog = make(map[string]*message)
+		b.catalogs[key] = catalog
+		b.locales = append(b.locales, locale)
+	}
+	for k, m := range flat {
+		catalog[k] = m
+	}
+	return nil
+}
+
+// LoadFile loads a .json or .toml catalog, the locale is the last dotted part of
+// the file name, e.g. zh-CN.json or messages.en.toml
+func (b *Bundle) LoadFile(filename string) error {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return err
+	}
+	return b.parse(filename, data)
+}
+
+// LoadFS loads the catalogs of fsys matching patterns, fsys is usually an embed.FS
+func (b *Bundle) LoadFS(fsys fs.FS, patterns ...string) error {
+	for _, pattern := range patterns {
+		files, err := fs.Glob(fsys, pattern)
+		if err != nil {
+			return err
+		}
+		for _, file := range files {
+			data, err := fs.ReadFile(fsys, file)
+			if err != nil {
+				return err
+			}
+			if err := b.parse(file, data); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (b *Bundle) parse(filename string, data []byte) error {
+	ext := path.Ext(filename)
+	name := strings.TrimSuffix(path.Base(filename), ext)
+	locale := name[strings.LastIndexByte(name, '.')+1:]
+
+	var table map[string]interface{}
+	var err error
+	switch strings.ToLower(ext) {
+	case ".json":
+		table, err = parseJSON(data)
+	case ".toml":
+		table, err = parseTOML(data)
+	default:
+		return fmt.Errorf("i18n: unknown catalog format %s", filename)
+	}
+	if err != nil {
+		return fmt.Errorf("%s: %v", filename, err)
+	}
+	return b.AddMessages(locale, table)
+}
+
+// Locales returns the loaded locales
+func (b *Bundle) Locales() []string {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return append([]string(nil), b.locales...)
+}
+
+// Match returns the loaded locale best matching the wanted ones in order of
+// preference, a language also matches its regional locales, e.g. en matches en-US
+func (b *Bundle) Match(wanted ...string) (string, bool) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	for _, tag := range wanted {
+		tag = strings.ToLower(strings.ReplaceAll(strings.TrimSpace(tag), "_", "-"))
+		if tag == "" {
+			continue
+		}
+		if _, ok := b.catalogs[tag]; ok {
+			return b.name(tag), true
+		}
+		base := baseLanguage(tag)
+		if _, ok := b.catalogs[base]; ok {
+			return b.name(base), true
+		}
+		for _, locale := range b.locales {
+			if baseLanguage(strings.ToLower(locale)) == base {
+				return locale, true
+			}
+		}
+	}
+	return b.defaultLocale, false
+}
+
+// name returns the locale as loaded, the caller must hold b.mu
+func (b *Bundle) name(key string) string {
+	for _, locale := range b.locales {
+		if strings.ToLower(locale) == key {
+			return locale
+		}
+	}
+	return key
+}
+
+func baseLanguage(tag string) string {
+	if i := strings.IndexByte(tag, '-'); i >= 0 {
+		return tag[:i]
+	}
+	return tag
+}
+
+// Localizer translates the messages of one locale, falling back to its base
+// language and then to the default locale
+type Localizer struct {
+	bundle *Bundle
+	locale string
+	chain  []string
+	rule   PluralRule
+}
+
+// Localizer returns the Localizer of locale
+func (b *Bundle) Localizer(locale string) *Localizer {
+	key := strings.ToLower(locale)
+	chain := []string{key}
+	if base := baseLanguage(key); base != key {
+		chain = append(chain, base)
+	}
+	chain = append(chain, strings.ToLower(b.defaultLocale))
+	return &Localizer{bundle: b, locale: locale, chain: chain, rule: pluralRule(key)}
+}
+
+// Locale returns the locale of l
+func (l *Localizer) Locale() string {
+	return l.locale
+}
+
+// Translate returns the message of key, or key if no catalog has it.
+// The first number of args is the plural count, available as {count};
+// the other args fill {0}, {1}... by position, a map fills {name} by name
+func (l *Localizer) Translate(key string, args ...interface{}) string {
+	m := l.lookup(key)
+	if m == nil {
+		return key
+	}
+
+	vars := make(map[string]string)
+	var count *float64
+	for i, arg := range args {
+		if named, ok := toMap(arg); ok {
+			for name, value := range named {
+				vars[name] = fmt.Sprint(value)
+			}
+			continue
+		}
+		vars[strconv.Itoa(i)] = fmt.Sprint(arg)
+		if n, ok := toNumber(arg); ok && count == nil {
+			count = &n
+			if _, ok := vars["count"]; !ok {
+				vars["count"] = fmt.Sprint(arg)
+			}
+		}
+	}
+
+	text := m.text
+	if m.plural != nil {
+		var n float64
+		if count != nil {
+			n = *count
+		}
+		text = m.plural[l.rule(n)]
+		if zero, ok := m.plural["zero"]; ok && n == 0 {
+			text = zero
+		}
+		if text == "" {
+			text = m.plural["other"]
+		}
+	}
+	return interpolate(text, vars)
+}
+
+func (l *Localizer) lookup(key string) *message {
+	l.bundle.mu.RLock()
+	defer l.bundle.mu.RUnlock()
+	for _, locale := range l.chain {
+		if m, ok := l.bundle.catalogs[locale][key]; ok {
+			return m
+		}
+	}
+	return nil
+}
+
+func toMap(arg interface{}) (map[string]interface{}, bool) {
+	switch v := arg.(type) {
+	case map[string]interface{}:
+		return v, true
+	case tinyGin.H:
+		return v, true
+	}
+	return nil, false
+}
+
+func toNumber(arg interface{}) (float64, bool) {
+	v := reflect.ValueOf(arg)
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(v.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), true
+	}
+	return 0, false
+}
+
+// interpolate replaces {name} with vars, unknown names are kept
+func interpolate(text string, vars map[string]string) string {
+	if !strings.Contains(text, "{") {
+		return text
+	}
+	var b strings.Builder
+	for {
+		start := strings.IndexByte(text, '{')
+		if start < 0 {
+			break
+		}
+		end := strings.IndexByte(text[start:], '}')
+		if end < 0 {
+			break
+		}
+		name := text[start+1 : start+end]
+		b.WriteString(text[:start])
+		if value, ok := vars[name]; ok {
+			b.WriteString(value)
+		} else {
+			b.WriteString(text[start : start+end+1])
+		}
+		text = text[start+end+1:]
+	}
+	b.WriteString(text)
+	return b.String()
+}
+
+// Options configures the i18n middleware, zero values fall back to the defaults
+type Options struct {
+	QueryParam string // default "lang", a matched value is remembered in the cookie
+	CookieName string // default "lang"
+}
+
+// New returns the middleware negotiating the locale of a request from the query param,
+// the cookie and then Accept-Language, and setting the Translator used by c.T
+func New(bundle *Bundle, opts Options) tinyGin.HandlerFunc {
+	if opts.QueryParam == "" {
+		opts.QueryParam = "lang"
+	}
+	if opts.CookieName == "" {
+		opts.CookieName = "lang"
+	}
+	return func(c *tinyGin.Context) {
+		locale, ok := bundle.Match(c.Query(opts.QueryParam))
+		if ok {
+			http.SetCookie(c.Writer, &http.Cookie{
+				Name:     opts.CookieName,
+				Value:    locale,
+				Path:     "/",
+				MaxAge:   86400 * 365,
+				SameSite: http.SameSiteLaxMode,
+			})
+		} else {
+			var wanted []string
+			if cookie, err := c.Req.Cookie(opts.CookieName); err == nil {
+				wanted = append(wanted, cookie.Value)
+			}
+			wanted = append(wanted, parseAcceptLanguage(c.Req.Header.Get("Accept-Language"))...)
+			locale, _ = bundle.Match(wanted...)
+		}
+		c.Set(tinyGin.TranslatorKey, bundle.Localizer(locale))
+		c.SetHeader("Content-Language", locale)
+		c.SetHeader("Vary", "Accept-Language, Cookie")
+		c.Next()
+	}
+}
+
+// Default returns the Localizer of the request, nil without the i18n middleware
+func Default(c *tinyGin.Context) *Localizer {
+	l, _ := c.Keys[tinyGin.TranslatorKey].(*Localizer)
+	return l
+}
+
+// parseAcceptLanguage returns the tags of the header by descending quality
+// refer https://www.rfc-editor.org/rfc/rfc9110#section-12.5.4
+func parseAcceptLanguage(header string) []string {
+	type weighted struct {
+		tag string
+		q   float64
+	}
+	var tags []weighted
+	for _, part := range strings.Split(header, ",") {
+		fields := strings.Split(part, ";")
+		tag := strings.TrimSpace(fields[0])
+		if tag == "" || tag == "*" {
+			continue
+		}
+		q := 1.0
+		for _, param := range fields[1:] {
+			param = strings.TrimSpace(param)
+			if strings.HasPrefix(param, "q=") {
+				if v, err := strconv.ParseFloat(param[2:], 64); err == nil {
+					q = v
+				}
+			}
+		}
+		if q > 0 {
+			tags = append(tags, weighted{tag, q})
+		}
+	}
+	sort.SliceStable(tags, func(i, j int) bool { return tags[i].q > tags[j].q })
+	result := make([]string, len(tags))
+	for i, t := range tags {
+		result[i] = t.tag
+	}
+	return result
+}
+
+// FuncMap returns the T template func for Engine.SetFuncMap, use it as {{ T .ctx "key" args... }}
+// where ctx is the *tinyGin.Context of the request
+func FuncMap() template.FuncMap {
+	return template.FuncMap{
+		"T": func(c *tinyGin.Context, key string, args ...interface{}) string {
+			return c.T(key, args...)
+		},
+	}
+}
@@ -0,0 +1,122 @@
+package i18n
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+
+	"tinyGin"
+)
+
+var catalogs = fstest.MapFS{
+	"locales/en.json": {Data: []byte(`{
+		"hello": "Hello, {name}!",
+		"cart": {
+			"items": {"zero": "Your cart is empty", "one": "{count} item", "other": "{count} items"}
+		},
+		"only_en": "English only"
+	}`)},
+	"locales/messages.ru.toml": {Data: []byte(`
+# Russian
+hello = "Привет, {name}!"
+
+[cart.items]
+one = "{count} товар"
+few = "{count} товара"
+many = "{count} товаров"
+`)},
+	"locales/zh-CN.toml": {Data: []byte(`hello = '你好，{name}！'  # literal string
+cart.items = "{0} 件商品"
+`)},
+}
+
+func newTestBundle(t *testing.T) *Bundle {
+	t.Helper()
+	b := NewBundle("en")
+	if err := b.LoadFS(catalogs, "locales/*"); err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+func TestLocalizer_Translate(t *testing.T) {
+	b := newTestBundle(t)
+	en, ru, zh := b.Localizer("en"), b.Localizer("ru"), b.Localizer("zh-CN")
+	cases := []struct {
+		l    *Localizer
+		key  string
+		args []interface{}
+		want string
+	}{
+		{en, "hello", []interface{}{tinyGin.H{"name": "Tom"}}, "Hello, Tom!"},
+		{en, "cart.items", []interface{}{0}, "Your cart is empty"},
+		{en, "cart.items", []interface{}{1}, "1 item"},
+		{en, "cart.items", []interface{}{5}, "5 items"},
+		{ru, "hello", []interface{}{map[string]interface{}{"name": "Том"}}, "Привет, Том!"},
+		{ru, "cart.items", []interface{}{21}, "21 товар"},
+		{ru, "cart.items", []interface{}{3}, "3 товара"},
+		{ru, "cart.items", []interface{}{11}, "11 товаров"},
+		{ru, "only_en", nil, "English only"},
+		{zh, "hello", []interface{}{tinyGin.H{"name": "汤姆"}}, "你好，汤姆！"},
+		{zh, "cart.items", []interface{}{2}, "2 件商品"},
+		{zh, "missing", nil, "missing"},
+	}
+	for _, tc := range cases {
+		if got := tc.l.Translate(tc.key, tc.args...); got != tc.want {
+			t.Fatalf("%s %s %v: expect %q, but got %q", tc.l.Locale(), tc.key, tc.args, tc.want, got)
+		}
+	}
+}
+
+func TestNew(t *testing.T) {
+	tinyGin.SetMode(tinyGin.TestMode)
+	r := tinyGin.New()
+	r.SetFuncMap(FuncMap())
+	r.LoadHTMLFS(fstest.MapFS{
+		"index.tmpl": {Data: []byte(`{{ T .ctx "hello" .user }} {{ T .ctx "cart.items" 2 }}`)},
+	}, "*.tmpl")
+	r.Use(New(newTestBundle(t), Options{}))
+	r.GET("/", func(c *tinyGin.Context) {
+		c.String(http.StatusOK, "%s", c.T("hello", tinyGin.H{"name": "Tom"}))
+	})
+	r.GET("/page", func(c *tinyGin.Context) {
+		c.HTML(http.StatusOK, "index.tmpl", tinyGin.H{"ctx": c, "user": tinyGin.H{"name": "Tom"}})
+	})
+
+	cases := []struct {
+		target   string
+		header   string
+		cookie   string
+		body     string
+		language string
+	}{
+		{"/", "ru;q=0.9, zh-TW;q=0.8, en;q=0.1", "", "Привет, Tom!", "ru"},
+		{"/", "zh-TW, en;q=0.5", "", "你好，Tom！", "zh-CN"},
+		{"/", "de-DE", "", "Hello, Tom!", "en"},
+		{"/", "ru", "zh-CN", "你好，Tom！", "zh-CN"},
+		{"/?lang=ru", "zh", "zh-CN", "Привет, Tom!", "ru"},
+		{"/page", "ru", "", "Привет, Tom! 2 товара", "ru"},
+	}
+	for _, tc := range cases {
+		req := httptest.NewRequest("GET", tc.target, nil)
+		req.Header.Set("Accept-Language", tc.header)
+		if tc.cookie != "" {
+			req.AddCookie(&http.Cookie{Name: "lang", Value: tc.cookie})
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		if w.Body.String() != tc.body || w.Header().Get("Content-Language") != tc.language {
+			t.Fatalf("%s %s: expect %q in %s, but got %q in %s", tc.target, tc.header, tc.body, tc.language,
+				w.Body.String(), w.Header().Get("Content-Language"))
+		}
+	}
+}
+
+func TestParseTOML_Error(t *testing.T) {
+	for _, data := range []string{"[cart", "hello", `hello = "unterminated`, "hello = 42"} {
+		if _, err := parseTOML([]byte(data)); err == nil {
+			t.Fatalf("%q: expect an error", data)
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package i18n
+
+import (
+	"strings"
+	"sync"
+)
+
+// PluralRule returns the plural category of n: zero, one, two, few, many or other
+// refer https://www.unicode.org/cldr/charts/latest/supplemental/language_plural_rules.html
+type PluralRule func(n float64) string
+
+func oneOther(n float64) string {
+	if n == 1 {
+		return "one"
+	}
+	return "other"
+}
+
+func zeroOneOther(n float64) string {
+	if n >= 0 && n < 2 {
+		return "one"
+	}
+	return "other"
+}
+
+func otherOnly(float64) string {
+	return "other"
+}
+
+// slavic is the rule of ru and uk for integers
+func slavic(n float64) string {
+	i := int64(n)
+	if float64(i) != n {
+		return "other"
+	}
+	mod10, mod100 := i%10, i%100
+	switch {
+	case mod10 == 1 && mod100 != 11:
+		return "one"
+	case mod10 >= 2 && mod10 <= 4 && (mod100 < 12 || mod100 > 14):
+		return "few"
+	}
+	return "many"
+}
+
+func polish(n float64) string {
+	i := int64(n)
+	if float64(i) != n {
+		return "other"
+	}
+	mod10, mod100 := i%10, i%100
+	switch {
+	case i == 1:
+		return "one"
+	case mod10 >= 2 && mod10 <= 4 && (mod100 < 12 || mod100 > 14):
+		return "few"
+	}
+	return "many"
+}
+
+func czech(n float64) string {
+	switch {
+	case n == 1:
+		return "one"
+	case n >= 2 && n <= 4 && n == float64(int64(n)):
+		return "few"
+	}
+	return "other"
+}
+
+var pluralRules = struct {
+	sync.RWMutex
+	rules map[string]PluralRule
+}{
+	rules: map[string]PluralRule{
+		"en": oneOther, "de": oneOther, "nl": oneOther, "sv": oneOther, "da": oneOther,
+		"nb": oneOther, "it": oneOther, "es": oneOther, "pt": oneOther, "el": oneOther,
+		"fr": zeroOneOther,
+		"zh": otherOnly, "ja": otherOnly, "ko": otherOnly, "vi": otherOnly, "th": otherOnly, "id": otherOnly,
+		"ru": slavic, "uk": slavic,
+		"pl": polish,
+		"cs": czech, "sk": czech,
+	},
+}
+
+// RegisterPluralRule sets the rule of a language, e.g. "ar"
+func RegisterPluralRule(lang string, rule PluralRule) {
+	pluralRules.Lock()
+	defer pluralRules.Unlock()
+	pluralRules.rules[strings.ToLower(lang)] = rule
+}
+
+// pluralRule returns the rule of the language of locale, one/other if it is unknown
+func pluralRule(locale string) PluralRule {
+	pluralRules.RLock()
+	defer pluralRules.RUnlock()
+	if rule, ok := pluralRules.rules[locale]; ok {
+		return rule
+	}
+	if rule, ok := pluralRules.rules[baseLanguage(locale)]; ok {
+		return rule
+	}
+	return oneOther
+}
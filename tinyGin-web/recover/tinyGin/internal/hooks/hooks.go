@@ -0,0 +1,9 @@
+// Package hooks gives the subpackages of tinyGin the unexported constructors they need,
+// without adding them to the API of tinyGin
+package hooks
+
+import "net/http"
+
+// NewTestContext is set by tinyGin, it returns a *tinyGin.Context of req writing to w
+// and the new *tinyGin.Engine owning it
+var NewTestContext func(w http.ResponseWriter, req *http.Request) (c interface{}, engine interface{})
@@ -0,0 +1,185 @@
+package tinyGin
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// JSONEncoder is implemented by *json.Encoder and the encoders of most JSON libraries
+type JSONEncoder interface {
+	Encode(v interface{}) error
+}
+
+// JSONDecoder is implemented by *json.Decoder and the decoders of most JSON libraries
+type JSONDecoder interface {
+	Decode(v interface{}) error
+	DisallowUnknownFields()
+	UseNumber()
+}
+
+// JSONCodec creates the encoders and decoders used by the Context,
+// set Engine.JSONCodec to plug in another JSON library
+type JSONCodec interface {
+	NewEncoder(w io.Writer) JSONEncoder
+	NewDecoder(r io.Reader) JSONDecoder
+}
+
+// StdJSON is the JSONCodec of encoding/json
+type StdJSON struct{}
+
+func (StdJSON) NewEncoder(w io.Writer) JSONEncoder {
+	return json.NewEncoder(w)
+}
+
+func (StdJSON) NewDecoder(r io.Reader) JSONDecoder {
+	return json.NewDecoder(r)
+}
+
+func (c *Context) jsonCodec() JSONCodec {
+	if c.engine == nil || c.engine.JSONCodec == nil {
+		return StdJSON{}
+	}
+	return c.engine.JSONCodec
+}
+
+// JSONOption is an option of the decoders of DecodeJSON and NDJSONReader
+type JSONOption int
+
+const (
+	// DisallowUnknownFields fails on the object keys which match no field
+	DisallowUnknownFields JSONOption = iota
+	// UseNumber decodes numbers into interface{} as json.Number instead of float64
+	UseNumber
+)
+
+func (c *Context) newJSONDecoder(r io.Reader, opts []JSONOption) JSONDecoder {
+	dec := c.jsonCodec().NewDecoder(r)
+	for _, opt := range opts {
+		switch opt {
+		case DisallowUnknownFields:
+			dec.DisallowUnknownFields()
+		case UseNumber:
+			dec.UseNumber()
+		}
+	}
+	return dec
+}
+
+// DecodeJSON decodes the body into obj, errors are returned as *BindingError
+func (c *Context) DecodeJSON(obj interface{}, opts ...JSONOption) error {
+	if err := c.newJSONDecoder(c.Req.Body, opts).Decode(obj); err != nil {
+		return &BindingError{Source: "body", Err: err}
+	}
+	return nil
+}
+
+// ErrLineTooLong is returned by NDJSONReader for a line over MaxLineSize
+var ErrLineTooLong = errors.New("line too long")
+
+// NDJSONReader reads the newline delimited JSON values of a body one by one
+// refer https://github.com/ndjson/ndjson-spec
+type NDJSONReader struct {
+	// MaxLineSize is the max length of a line in bytes, default 1MB
+	MaxLineSize int
+
+	c    *Context
+	r    *bufio.Reader
+	opts []JSONOption
+	line int
+	buf  []byte
+	err  error // a line over MaxLineSize stops the reader
+}
+
+// NDJSONReader returns a reader of the body, blank lines are skipped
+func (c *Context) NDJSONReader(opts ...JSONOption) *NDJSONReader {
+	return &NDJSONReader{MaxLineSize: 1 << 20, c: c, r: bufio.NewReader(c.Req.Body), opts: opts}
+}
+
+// Read decodes the next value into v, it returns io.EOF after the last value.
+// An invalid line, a line with more than one value or a line over MaxLineSize is
+// returned as *BindingError with the line number
+func (nr *NDJSONReader) Read(v interface{}) error {
+	if nr.err != nil {
+		return nr.err
+	}
+	for {
+		line, err := nr.readLine()
+		if err == ErrLineTooLong {
+			nr.err = &BindingError{Source: fmt.Sprintf("body line %d", nr.line+1), Err: err}
+			return nr.err
+		}
+		if err != nil && err != io.EOF {
+			// the line may be cut short, e.g. by MaxBodySize, it is not decoded
+			nr.err = &BindingError{Source: "body", Err: err}
+			return nr.err
+		}
+		if len(line) == 0 && err == io.EOF {
+			return io.EOF
+		}
+		nr.line++
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 {
+			continue
+		}
+		dec := nr.c.newJSONDecoder(bytes.NewReader(line), nr.opts)
+		if err := dec.Decode(v); err != nil {
+			return &BindingError{Source: fmt.Sprintf("body line %d", nr.line), Err: err}
+		}
+		var extra interface{}
+		if err := dec.Decode(&extra); err != io.EOF {
+			return &BindingError{Source: fmt.Sprintf("body line %d", nr.line), Err: errors.New("unexpected data after the value")}
+		}
+		return nil
+	}
+}
+
+// readLine reads up to the next newline, at most MaxLineSize bytes are buffered
+func (nr *NDJSONReader) readLine() ([]byte, error) {
+	nr.buf = nr.buf[:0]
+	for {
+		chunk, err := nr.r.ReadSlice('\n')
+		if len(nr.buf)+len(chunk) > nr.MaxLineSize {
+			return nil, ErrLineTooLong
+		}
+		nr.buf = append(nr.buf, chunk...)
+		if err != bufio.ErrBufferFull {
+			return nr.buf, err
+		}
+	}
+}
+
+// NDJSONWriter writes newline delimited JSON values, each one is flushed to the client
+type NDJSONWriter struct {
+	c   *Context
+	buf bytes.Buffer
+}
+
+// NDJSON writes the status and the application/x-ndjson header, values are then written
+// by the returned writer
+func (c *Context) NDJSON(code int) *NDJSONWriter {
+	c.SetHeader("Content-Type", "application/x-ndjson")
+	c.SetHeader("X-Content-Type-Options", "nosniff")
+	c.Status(code)
+	return &NDJSONWriter{c: c}
+}
+
+// Write writes v on one line
+func (nw *NDJSONWriter) Write(v interface{}) error {
+	nw.buf.Reset()
+	if err := nw.c.jsonCodec().NewEncoder(&nw.buf).Encode(v); err != nil {
+		return err
+	}
+	line := append(bytes.TrimRight(nw.buf.Bytes(), "\n"), '\n')
+	if _, err := nw.c.Writer.Write(line); err != nil {
+		return err
+	}
+	if f, ok := nw.c.Writer.(http.Flusher); ok {
+		f.Flush()
+	}
+	return nil
+}
@@ -0,0 +1,110 @@
+package tinyGin
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// countingJSON wraps encoding/json to check the engine uses the codec
+type countingJSON struct {
+	StdJSON
+	encoders int
+}
+
+func (j *countingJSON) NewEncoder(w io.Writer) JSONEncoder {
+	j.encoders++
+	return j.StdJSON.NewEncoder(w)
+}
+
+func TestEngine_JSONCodec(t *testing.T) {
+	codec := &countingJSON{}
+	r := New()
+	r.JSONCodec = codec
+	r.GET("/", func(c *Context) { c.JSON(http.StatusOK, H{"name": "tom"}) })
+	if w := serve(r, "GET", "/"); w.Body.String() != "{\"name\":\"tom\"}\n" || codec.encoders != 1 {
+		t.Fatalf("expect the codec to encode, but got %q with %d encoders", w.Body.String(), codec.encoders)
+	}
+}
+
+func TestContext_DecodeJSON(t *testing.T) {
+	body := `{"id": 12345678901234567890, "extra": true}`
+	request := func() *Context {
+		return newContext(httptest.NewRecorder(), httptest.NewRequest("POST", "/", strings.NewReader(body)))
+	}
+
+	var strict struct {
+		ID json.Number `json:"id"`
+	}
+	var bindingErr *BindingError
+	if err := request().DecodeJSON(&strict, DisallowUnknownFields); !errors.As(err, &bindingErr) ||
+		!strings.Contains(err.Error(), "unknown field") {
+		t.Fatalf("expect an unknown field error, but got %v", err)
+	}
+
+	var loose map[string]interface{}
+	if err := request().DecodeJSON(&loose, UseNumber); err != nil || loose["id"] != json.Number("12345678901234567890") {
+		t.Fatalf("expect the id as json.Number, but got %#v %v", loose["id"], err)
+	}
+}
+
+func TestContext_NDJSON(t *testing.T) {
+	type record struct {
+		N int `json:"n"`
+	}
+	r := New()
+	r.POST("/bulk", func(c *Context) {
+		reader := c.NDJSONReader(DisallowUnknownFields)
+		writer := c.NDJSON(http.StatusOK)
+		for {
+			var rec record
+			err := reader.Read(&rec)
+			if err == io.EOF {
+				return
+			}
+			if err != nil {
+				_ = writer.Write(H{"error": err.Error()})
+				return
+			}
+			rec.N *= 2
+			_ = writer.Write(rec)
+		}
+	})
+
+	req := httptest.NewRequest("POST", "/bulk", strings.NewReader("{\"n\": 1}\n\n{\"n\": 2}\r\n{\"m\": 3}\n{\"n\": 4}"))
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	want := "{\"n\":2}\n{\"n\":4}\n{\"error\":\"invalid body line 4: json: unknown field \\\"m\\\"\"}\n"
+	if w.Header().Get("Content-Type") != "application/x-ndjson" || w.Body.String() != want || !w.Flushed {
+		t.Fatalf("expect %q, but got %q", want, w.Body.String())
+	}
+}
+
+func TestNDJSONReader_Limits(t *testing.T) {
+	read := func(body string, max int) error {
+		c := newContext(httptest.NewRecorder(), httptest.NewRequest("POST", "/bulk", strings.NewReader(body)))
+		reader := c.NDJSONReader()
+		reader.MaxLineSize = max
+		for {
+			var v map[string]interface{}
+			if err := reader.Read(&v); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := read("{}\n{} {}\n", 64); err == nil || err.Error() != "invalid body line 2: unexpected data after the value" {
+		t.Fatalf("expect the second value of a line to be rejected, but got %v", err)
+	}
+	long := "{\"s\": \"" + strings.Repeat("x", 100) + "\"}\n"
+	if err := read("{}\n"+long, 64); !errors.Is(err, ErrLineTooLong) || !strings.Contains(err.Error(), "line 2") {
+		t.Fatalf("expect ErrLineTooLong on line 2, but got %v", err)
+	}
+	if err := read("{}\n"+long, 1<<20); err != io.EOF {
+		t.Fatalf("expect the long line to be read within the limit, but got %v", err)
+	}
+}
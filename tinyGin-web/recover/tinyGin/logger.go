@@ -1,10 +1,10 @@
 package tinyGin
 
 import (
-	"log"
 	"time"
 )
 
+// Logger logs the status, URI and latency of requests to DefaultWriter, except in TestMode
 func Logger() HandlerFunc {
 	return func(c *Context) {
 		// Start timer
@@ -12,6 +12,9 @@ func Logger() HandlerFunc {
 		// Process request
 		c.Next()
 		// Calculate resolution time
-		log.Printf("[%d] %s in %v", c.StatusCode, c.Req.RequestURI, time.Since(t))
+		if Mode() == TestMode {
+			return
+		}
+		newLogger(DefaultWriter).Printf("[%d] %s in %v", c.StatusCode, c.Req.RequestURI, time.Since(t))
 	}
 }
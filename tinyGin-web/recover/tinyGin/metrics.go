@@ -0,0 +1,224 @@
+package tinyGin
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultBuckets are the latency histogram buckets in seconds,
+// the same as the Prometheus client defaults
+var DefaultBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+// unmatchedRoute labels requests that did not match any route,
+// so raw paths never become label values
+const unmatchedRoute = "unmatched"
+
+type metricKey struct {
+	method string
+	route  string
+	status string
+}
+
+type histogram struct {
+	counts []uint64 // counts[i] is the number of observations <= buckets[i]
+	sum    float64
+	count  uint64
+}
+
+// Metrics records request counts, latency histograms and in-flight gauges
+type Metrics struct {
+	mu        sync.Mutex
+	buckets   []float64
+	requests  map[metricKey]uint64
+	durations map[metricKey]*histogram
+	inFlight  map[metricKey]int64 // status is always empty
+}
+
+// NewMetrics is the constructor of Metrics, DefaultBuckets is used when no bucket is given
+func NewMetrics(buckets ...float64) *Metrics {
+	if len(buckets) == 0 {
+		buckets = DefaultBuckets
+	}
+	buckets = append([]float64(nil), buckets...)
+	sort.Float64s(buckets)
+	return &Metrics{
+		buckets:   buckets,
+		requests:  make(map[metricKey]uint64),
+		durations: make(map[metricKey]*histogram),
+		inFlight:  make(map[metricKey]int64),
+	}
+}
+
+// EnableMetrics records metrics for every request and exposes them on path
+func (engine *Engine) EnableMetrics(path string) *Metrics {
+	m := NewMetrics()
+	engine.Use(m.Middleware())
+	engine.GET(path, m.Handler())
+	return m
+}
+
+func routeOf(c *Context) string {
+	if c.fullPath == "" {
+		return unmatchedRoute
+	}
+	return c.fullPath
+}
+
+func statusClass(code int) string {
+	if code == 0 {
+		// nothing was written explicitly, net/http answers 200
+		code = http.StatusOK
+	}
+	return strconv.Itoa(code/100) + "xx"
+}
+
+// otherMethod labels the requests of non-standard methods, so clients can't add label values
+const otherMethod = "OTHER"
+
+var standardMethods = map[string]bool{
+	http.MethodGet: true, http.MethodHead: true, http.MethodPost: true, http.MethodPut: true,
+	http.MethodPatch: true, http.MethodDelete: true, http.MethodConnect: true,
+	http.MethodOptions: true, http.MethodTrace: true,
+}
+
+func methodOf(c *Context) string {
+	if standardMethods[c.Method] {
+		return c.Method
+	}
+	return otherMethod
+}
+
+// Middleware records the request into m. A panic is recorded as 500 and raised again
+// for Recovery, which writes the response after this middleware returned
+func (m *Metrics) Middleware() HandlerFunc {
+	return func(c *Context) {
+		gauge := metricKey{method: methodOf(c), route: routeOf(c)}
+		m.mu.Lock()
+		m.inFlight[gauge]++
+		m.mu.Unlock()
+
+		t := time.Now()
+		defer func() {
+			code := c.StatusCode
+			err := recover()
+			if err != nil {
+				code = http.StatusInternalServerError
+			}
+			m.observe(gauge, code, time.Since(t))
+			if err != nil {
+				panic(err)
+			}
+		}()
+		c.Next()
+	}
+}
+
+func (m *Metrics) observe(gauge metricKey, code int, d time.Duration) {
+	key := gauge
+	key.status = statusClass(code)
+	seconds := d.Seconds()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.inFlight[gauge]--
+	m.requests[key]++
+	h, ok := m.durations[key]
+	if !ok {
+		h = &histogram{counts: make([]uint64, len(m.buckets))}
+		m.durations[key] = h
+	}
+	for i, upper := range m.buckets {
+		if seconds <= upper {
+			h.counts[i]++
+		}
+	}
+	h.sum += seconds
+	h.count++
+}
+
+// Handler exposes the metrics in the Prometheus text format
+func (m *Metrics) Handler() HandlerFunc {
+	return func(c *Context) {
+		c.SetHeader("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		c.Status(http.StatusOK)
+		c.Writer.Write([]byte(m.String()))
+	}
+}
+
+// String renders the metrics in the Prometheus text format
+func (m *Metrics) String() string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var b strings.Builder
+	b.WriteString("# HELP tinygin_http_requests_total Total number of HTTP requests.\n")
+	b.WriteString("# TYPE tinygin_http_requests_total counter\n")
+	for _, key := range sortedKeys(m.requests) {
+		fmt.Fprintf(&b, "tinygin_http_requests_total{%s} %d\n", key.labels(), m.requests[key])
+	}
+
+	b.WriteString("# HELP tinygin_http_request_duration_seconds HTTP request latency in seconds.\n")
+	b.WriteString("# TYPE tinygin_http_request_duration_seconds histogram\n")
+	for _, key := range sortedKeys(m.requests) {
+		h := m.durations[key]
+		labels := key.labels()
+		for i, upper := range m.buckets {
+			fmt.Fprintf(&b, "tinygin_http_request_duration_seconds_bucket{%s,le=\"%s\"} %d\n",
+				labels, formatFloat(upper), h.counts[i])
+		}
+		fmt.Fprintf(&b, "tinygin_http_request_duration_seconds_bucket{%s,le=\"+Inf\"} %d\n", labels, h.count)
+		fmt.Fprintf(&b, "tinygin_http_request_duration_seconds_sum{%s} %s\n", labels, formatFloat(h.sum))
+		fmt.Fprintf(&b, "tinygin_http_request_duration_seconds_count{%s} %d\n", labels, h.count)
+	}
+
+	b.WriteString("# HELP tinygin_http_requests_in_flight Number of HTTP requests being served.\n")
+	b.WriteString("# TYPE tinygin_http_requests_in_flight gauge\n")
+	gauges := make(map[metricKey]uint64, len(m.inFlight))
+	for key := range m.inFlight {
+		gauges[key] = 0
+	}
+	for _, key := range sortedKeys(gauges) {
+		fmt.Fprintf(&b, "tinygin_http_requests_in_flight{%s} %d\n", key.labels(), m.inFlight[key])
+	}
+	return b.String()
+}
+
+func (k metricKey) labels() string {
+	labels := fmt.Sprintf("method=\"%s\",route=\"%s\"", escapeLabel(k.method), escapeLabel(k.route))
+	if k.status != "" {
+		labels += fmt.Sprintf(",status=\"%s\"", k.status)
+	}
+	return labels
+}
+
+func sortedKeys(m map[metricKey]uint64) []metricKey {
+	keys := make([]metricKey, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].route != keys[j].route {
+			return keys[i].route < keys[j].route
+		}
+		if keys[i].method != keys[j].method {
+			return keys[i].method < keys[j].method
+		}
+		return keys[i].status < keys[j].status
+	})
+	return keys
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func escapeLabel(value string) string {
+	return labelEscaper.Replace(value)
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
@@ -0,0 +1,55 @@
+package tinyGin
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestMetrics(t *testing.T) {
+	r := New()
+	r.EnableMetrics("/metrics")
+	r.GET("/hello/:name", func(c *Context) {
+		c.String(http.StatusOK, "hello %s", c.Param("name"))
+	})
+
+	for _, path := range []string{"/hello/tom", "/hello/jack", "/missing"} {
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", path, nil))
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/metrics", nil))
+
+	body := w.Body.String()
+	expects := []string{
+		`tinygin_http_requests_total{method="GET",route="/hello/:name",status="2xx"} 2`,
+		`tinygin_http_requests_total{method="GET",route="unmatched",status="4xx"} 1`,
+		`tinygin_http_request_duration_seconds_bucket{method="GET",route="/hello/:name",status="2xx",le="+Inf"} 2`,
+		`tinygin_http_requests_in_flight{method="GET",route="/metrics"} 1`,
+	}
+	for _, expect := range expects {
+		if !strings.Contains(body, expect) {
+			t.Fatalf("expect %q in\n%s", expect, body)
+		}
+	}
+}
+
+func TestMetrics_PanicAndMethod(t *testing.T) {
+	r := New()
+	r.Use(Recovery())
+	m := r.EnableMetrics("/metrics")
+	r.GET("/panic", func(c *Context) { panic("boom") })
+	r.Handle("BREW", "/coffee", func(c *Context) { c.Status(http.StatusTeapot) })
+
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/panic", nil))
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("BREW", "/coffee", nil))
+	body := m.String()
+	for _, expect := range []string{
+		`tinygin_http_requests_total{method="GET",route="/panic",status="5xx"} 1`,
+		`tinygin_http_requests_total{method="OTHER",route="/coffee",status="4xx"} 1`,
+	} {
+		if !strings.Contains(body, expect) {
+			t.Fatalf("expect %q in\n%s", expect, body)
+		}
+	}
+}
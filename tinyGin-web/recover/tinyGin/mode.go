@@ -0,0 +1,75 @@
+package tinyGin
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"sync/atomic"
+)
+
+// EnvMode is the environment variable setting the initial mode
+const EnvMode = "TINYGIN_MODE"
+
+const (
+	// DebugMode logs routes and warnings, prints stack traces and reloads templates
+	DebugMode = "debug"
+	// ReleaseMode drops the debug messages and stack traces, errors and the requests
+	// seen by Logger are still logged
+	ReleaseMode = "release"
+	// TestMode logs nothing
+	TestMode = "test"
+)
+
+var (
+	// DefaultWriter is the output of Logger and the debug messages
+	DefaultWriter io.Writer = os.Stderr
+	// DefaultErrorWriter is the output of Recovery
+	DefaultErrorWriter io.Writer = os.Stderr
+)
+
+var mode atomic.Value
+
+func init() {
+	SetMode(os.Getenv(EnvMode))
+}
+
+// SetMode sets the mode of tinyGin, an empty value means DebugMode
+func SetMode(value string) {
+	switch value {
+	case "":
+		value = DebugMode
+	case DebugMode, ReleaseMode, TestMode:
+	default:
+		panic("tinyGin: unknown mode " + value + ", expect debug, release or test")
+	}
+	mode.Store(value)
+}
+
+// Mode returns the current mode
+func Mode() string {
+	return mode.Load().(string)
+}
+
+// IsDebugging reports if tinyGin runs in DebugMode
+func IsDebugging() bool {
+	return Mode() == DebugMode
+}
+
+func newLogger(w io.Writer) *log.Logger {
+	return log.New(w, "", log.LstdFlags)
+}
+
+// debugPrint logs only in DebugMode
+func debugPrint(format string, values ...interface{}) {
+	if IsDebugging() {
+		newLogger(DefaultWriter).Printf(format, values...)
+	}
+}
+
+// errorPrint logs unless in TestMode
+func errorPrint(format string, values ...interface{}) {
+	if Mode() != TestMode {
+		newLogger(DefaultErrorWriter).Print(fmt.Sprintf(format, values...))
+	}
+}
@@ -0,0 +1,88 @@
+package tinyGin
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestMain(m *testing.M) {
+	SetMode(TestMode)
+	os.Exit(m.Run())
+}
+
+// withMode runs f in mode with the output captured
+func withMode(value string, f func()) string {
+	var buf bytes.Buffer
+	writer, errorWriter := DefaultWriter, DefaultErrorWriter
+	DefaultWriter, DefaultErrorWriter = &buf, &buf
+	SetMode(value)
+	defer func() {
+		SetMode(TestMode)
+		DefaultWriter, DefaultErrorWriter = writer, errorWriter
+	}()
+	f()
+	return buf.String()
+}
+
+func TestSetMode(t *testing.T) {
+	panicky := func() *Engine {
+		r := New()
+		r.Use(Logger(), Recovery())
+		r.GET("/panic", func(c *Context) { panic("boom") })
+		serve(r, "GET", "/panic")
+		return r
+	}
+
+	var reload bool
+	out := withMode(DebugMode, func() { reload = panicky().HTMLAutoReload.enabled() })
+	if !strings.Contains(out, "Route  GET - /panic") || !strings.Contains(out, "boom\nTraceback:") ||
+		!strings.Contains(out, "[500] /panic") || !reload {
+		t.Fatalf("expect routes, traces and auto reload in debug mode, but got\n%s", out)
+	}
+
+	out = withMode(ReleaseMode, func() { reload = panicky().HTMLAutoReload.enabled() })
+	if strings.Contains(out, "Route") || strings.Contains(out, "Traceback") ||
+		!strings.Contains(out, "panic recovered: GET /panic: boom") || !strings.Contains(out, "[500] /panic") ||
+		reload {
+		t.Fatalf("expect the panic message and the request log in release mode, but got\n%s", out)
+	}
+
+	if out = withMode(TestMode, func() { panicky() }); out != "" {
+		t.Fatalf("expect no output in test mode, but got\n%s", out)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expect an unknown mode to panic")
+		}
+	}()
+	SetMode("production")
+}
+
+func TestContext_ClientIP(t *testing.T) {
+	r := New()
+	r.GET("/ip", func(c *Context) { c.String(http.StatusOK, "%s", c.ClientIP()) })
+	ip := func(remote string, forwarded string) string {
+		req := httptest.NewRequest("GET", "/ip", nil)
+		req.RemoteAddr = remote
+		req.Header.Set("X-Forwarded-For", forwarded)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w.Body.String()
+	}
+
+	if got := ip("10.0.0.2:1234", "203.0.113.9"); got != "10.0.0.2" {
+		t.Fatalf("expect X-Forwarded-For to be ignored without trusted proxies, but got %s", got)
+	}
+	r.TrustedProxies = []string{"10.0.0.0/8", "192.0.2.1"}
+	if got := ip("10.0.0.2:1234", "198.51.100.7, 203.0.113.9, 192.0.2.1"); got != "203.0.113.9" {
+		t.Fatalf("expect the first untrusted hop, but got %s", got)
+	}
+	if got := ip("198.51.100.1:1234", "203.0.113.9"); got != "198.51.100.1" {
+		t.Fatalf("expect an untrusted remote to be used, but got %s", got)
+	}
+}
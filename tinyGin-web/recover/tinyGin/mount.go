@@ -0,0 +1,84 @@
+package tinyGin
+
+import (
+	"bufio"
+	"errors"
+	"net"
+	"net/http"
+	"path"
+	"strings"
+)
+
+// WrapF adapts a http.HandlerFunc to a HandlerFunc
+func WrapF(f http.HandlerFunc) HandlerFunc {
+	return WrapH(f)
+}
+
+// WrapH adapts a http.Handler to a HandlerFunc, the status it writes is kept in c.StatusCode
+func WrapH(h http.Handler) HandlerFunc {
+	return func(c *Context) {
+		h.ServeHTTP(&statusWriter{ResponseWriter: c.Writer, c: c}, c.Req)
+	}
+}
+
+// Mount serves every request under prefix by h, with prefix stripped from the path.
+// h may be another *Engine, which then runs its own middlewares and 404 handler
+func (group *RouterGroup) Mount(prefix string, h http.Handler) {
+	absolutePrefix := path.Join(group.prefix, prefix)
+	handler := func(c *Context) {
+		req := c.Req.Clone(c.Req.Context())
+		req.URL.Path = stripPrefix(req.URL.Path, absolutePrefix)
+		if req.URL.RawPath != "" {
+			req.URL.RawPath = stripPrefix(req.URL.RawPath, absolutePrefix)
+		}
+		h.ServeHTTP(&statusWriter{ResponseWriter: c.Writer, c: c}, req)
+	}
+	group.Any(prefix, handler)
+	group.Any(path.Join(prefix, "/*mountpath"), handler)
+}
+
+func stripPrefix(p string, prefix string) string {
+	p = strings.TrimPrefix(p, prefix)
+	if !strings.HasPrefix(p, "/") {
+		p = "/" + p
+	}
+	return p
+}
+
+// statusWriter records the status written by a http.Handler into the Context
+type statusWriter struct {
+	http.ResponseWriter
+	c *Context
+}
+
+func (w *statusWriter) WriteHeader(code int) {
+	if w.c.StatusCode == 0 {
+		w.c.StatusCode = code
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *statusWriter) Write(b []byte) (int, error) {
+	if w.c.StatusCode == 0 {
+		w.c.StatusCode = http.StatusOK
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *statusWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+func (w *statusWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	h, ok := w.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, errors.New("tinyGin: response does not implement http.Hijacker")
+	}
+	return h.Hijack()
+}
+
+func (w *statusWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
@@ -0,0 +1,56 @@
+package tinyGin
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestRouterGroup_Mount(t *testing.T) {
+	sub := New()
+	sub.Use(func(c *Context) {
+		c.SetHeader("X-Sub", "yes")
+		c.Next()
+	})
+	sub.GET("/users/:id", func(c *Context) {
+		c.String(http.StatusOK, "user %s", c.Param("id"))
+	})
+	sub.GET("/", func(c *Context) {
+		c.String(http.StatusOK, "sub index")
+	})
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/vars", func(w http.ResponseWriter, req *http.Request) {
+		fmt.Fprintf(w, "vars at %s", req.URL.Path)
+	})
+
+	r := New()
+	r.Group("/v1").Mount("/sub", sub)
+	r.Mount("/debug", mux)
+	r.GET("/ping", WrapF(func(w http.ResponseWriter, req *http.Request) {
+		w.WriteHeader(http.StatusAccepted)
+	}))
+
+	cases := []struct {
+		method string
+		path   string
+		code   int
+		body   string
+	}{
+		{"GET", "/v1/sub/users/7", http.StatusOK, "user 7"},
+		{"GET", "/v1/sub", http.StatusOK, "sub index"},
+		{"GET", "/v1/sub/missing", http.StatusNotFound, "404 NOT FOUND: /missing\n"},
+		{"POST", "/v1/sub/users/7", http.StatusNotFound, "404 NOT FOUND: /users/7\n"},
+		{"GET", "/debug/vars", http.StatusOK, "vars at /vars"},
+		{"GET", "/ping", http.StatusAccepted, ""},
+	}
+	for _, tc := range cases {
+		w := serve(r, tc.method, tc.path)
+		if w.Code != tc.code || w.Body.String() != tc.body {
+			t.Fatalf("%s %s: expect %d %q, but got %d %q", tc.method, tc.path, tc.code, tc.body, w.Code, w.Body.String())
+		}
+	}
+	if w := serve(r, "GET", "/v1/sub/users/7"); w.Header().Get("X-Sub") != "yes" {
+		t.Fatal("expect the middleware of the sub engine to run")
+	}
+}
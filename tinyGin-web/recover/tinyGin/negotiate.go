@@ -0,0 +1,83 @@
+package tinyGin
+
+import (
+	"encoding/xml"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// negotiateOffers are the formats of Negotiate, the first one is the default
+var negotiateOffers = []string{"application/json", "application/xml", "text/xml", "application/yaml"}
+
+// Negotiate writes data as JSON, XML or YAML by the Accept header of the request,
+// JSON when the header is missing, 406 when no format is acceptable
+func (c *Context) Negotiate(code int, data interface{}) {
+	switch NegotiateFormat(c.Req.Header.Get("Accept"), negotiateOffers...) {
+	case "application/json":
+		c.JSON(code, data)
+	case "application/xml", "text/xml":
+		out, err := xml.Marshal(data)
+		if err != nil {
+			http.Error(c.Writer, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		c.SetHeader("Content-Type", "application/xml; charset=utf-8")
+		c.Data(code, append([]byte(xml.Header), out...))
+	case "application/yaml":
+		c.SetHeader("Content-Type", "application/yaml")
+		c.Data(code, encodeYAML(data))
+	default:
+		c.Problem(NewHTTPError(http.StatusNotAcceptable, "supported formats: "+strings.Join(negotiateOffers, ", ")))
+	}
+}
+
+// NegotiateFormat returns the offer most preferred by the Accept header, the first
+// offer if accept is empty, or "" if no offer is acceptable
+func NegotiateFormat(accept string, offers ...string) string {
+	if strings.TrimSpace(accept) == "" {
+		return offers[0]
+	}
+	best, bestQ, bestSpecificity := "", 0.0, -1
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+		q := 1.0
+		if value, ok := params["q"]; ok {
+			if q, err = strconv.ParseFloat(value, 64); err != nil {
+				continue
+			}
+		}
+		if q <= 0 {
+			continue
+		}
+		for _, offer := range offers {
+			specificity := matchMediaType(mediaType, offer)
+			if specificity < 0 {
+				continue
+			}
+			// a higher q wins, then a more specific range
+			if q > bestQ || (q == bestQ && specificity > bestSpecificity) {
+				best, bestQ, bestSpecificity = offer, q, specificity
+			}
+			break
+		}
+	}
+	return best
+}
+
+// matchMediaType returns 2 for an exact match, 1 for type/*, 0 for */* and -1 otherwise
+func matchMediaType(pattern string, offer string) int {
+	switch {
+	case pattern == offer:
+		return 2
+	case pattern == "*/*":
+		return 0
+	case strings.HasSuffix(pattern, "/*") && strings.HasPrefix(offer, pattern[:len(pattern)-1]):
+		return 1
+	}
+	return -1
+}
@@ -0,0 +1,461 @@
+package tinyGin
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"path"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// OpenAPIInfo is the info object of the generated document
+type OpenAPIInfo struct {
+	Title       string `json:"title"`
+	Version     string `json:"version"`
+	Description string `json:"description,omitempty"`
+}
+
+// OpenAPI generates an OpenAPI 3 document from the routes of the default host.
+// Path params become parameters, the Request and Response samples of a Route are
+// reflected into schemas under components, named by their package and Go type
+func (engine *Engine) OpenAPI(info OpenAPIInfo) map[string]interface{} {
+	return newSpecBuilder().build(info, engine.Routes(), "")
+}
+
+// ServeOpenAPI serves the document at path, as YAML if path ends with .yaml or .yml,
+// otherwise as JSON. The document is generated per request, so it follows route changes
+func (engine *Engine) ServeOpenAPI(path string, info OpenAPIInfo) *Route {
+	yaml := strings.HasSuffix(path, ".yaml") || strings.HasSuffix(path, ".yml")
+	return engine.GET(path, func(c *Context) {
+		doc := newSpecBuilder().build(info, engine.Routes(), path)
+		if !yaml {
+			c.JSON(http.StatusOK, doc)
+			return
+		}
+		c.SetHeader("Content-Type", "application/yaml")
+		c.Data(http.StatusOK, encodeYAML(doc))
+	})
+}
+
+type specBuilder struct {
+	schemas map[string]interface{}
+	names   map[reflect.Type]string
+}
+
+func newSpecBuilder() *specBuilder {
+	return &specBuilder{schemas: make(map[string]interface{}), names: make(map[reflect.Type]string)}
+}
+
+var (
+	importPathPrefix = regexp.MustCompile(`[^\[\],]*/`)
+	invalidNameChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+)
+
+// schemaName returns the component name of a named type: the last element of its package
+// path and its name, e.g. models.User or models.Page_models.User for models.Page[models.User].
+// Types of different packages with the same name get a numeric suffix
+func (b *specBuilder) schemaName(t reflect.Type) string {
+	if name, ok := b.names[t]; ok {
+		return name
+	}
+	name := importPathPrefix.ReplaceAllString(t.Name(), "")
+	if pkg := t.PkgPath(); pkg != "" {
+		name = path.Base(pkg) + "." + name
+	}
+	name = strings.Trim(invalidNameChars.ReplaceAllString(name, "_"), "_")
+	unique := name
+	for i := 2; ; i++ {
+		if _, taken := b.schemas[unique]; !taken {
+			break
+		}
+		unique = name + "_" + strconv.Itoa(i)
+	}
+	b.names[t] = unique
+	return unique
+}
+
+func (b *specBuilder) build(info OpenAPIInfo, routes []*Route, skip string) map[string]interface{} {
+	if info.Title == "" {
+		info.Title = "tinyGin"
+	}
+	if info.Version == "" {
+		info.Version = "0.0.0"
+	}
+	paths := make(map[string]interface{})
+	for _, route := range routes {
+		if route.Pattern == skip {
+			continue
+		}
+		path, params := openAPIPath(route.Pattern)
+		item, ok := paths[path].(map[string]interface{})
+		if !ok {
+			item = make(map[string]interface{})
+			paths[path] = item
+		}
+		item[strings.ToLower(route.Method)] = b.operation(route, params)
+	}
+
+	doc := map[string]interface{}{
+		"openapi": "3.0.3",
+		"info":    info,
+		"paths":   paths,
+	}
+	if len(b.schemas) > 0 {
+		doc["components"] = map[string]interface{}{"schemas": b.schemas}
+	}
+	return doc
+}
+
+func (b *specBuilder) operation(route *Route, params []interface{}) map[string]interface{} {
+	info := route.load()
+	op := make(map[string]interface{})
+	if info.summary != "" {
+		op["summary"] = info.summary
+	}
+	if info.description != "" {
+		op["description"] = info.description
+	}
+	if len(info.tags) > 0 {
+		op["tags"] = info.tags
+	}
+
+	if info.request != nil {
+		switch route.Method {
+		case http.MethodPost, http.MethodPut, http.MethodPatch:
+			op["requestBody"] = map[string]interface{}{
+				"required": true,
+				"content":  b.content(info.request),
+			}
+		default:
+			params = append(params, b.queryParams(reflect.TypeOf(info.request))...)
+		}
+	}
+	if len(params) > 0 {
+		op["parameters"] = params
+	}
+
+	responses := make(map[string]interface{})
+	for code, sample := range info.responses {
+		response := map[string]interface{}{"description": http.StatusText(code)}
+		if sample != nil {
+			response["content"] = b.content(sample)
+		}
+		responses[strconv.Itoa(code)] = response
+	}
+	if len(responses) == 0 {
+		responses["200"] = map[string]interface{}{"description": http.StatusText(http.StatusOK)}
+	}
+	op["responses"] = responses
+	return op
+}
+
+func (b *specBuilder) content(sample interface{}) map[string]interface{} {
+	return map[string]interface{}{
+		"application/json": map[string]interface{}{"schema": b.schema(reflect.TypeOf(sample))},
+	}
+}
+
+// openAPIPath converts a pattern such as /users/:id<int>/*path to /users/{id}/{path}
+func openAPIPath(pattern string) (string, []interface{}) {
+	parts := strings.Split(pattern, "/")
+	var params []interface{}
+	for i, part := range parts {
+		if part == "" || (part[0] != ':' && part[0] != '*') {
+			continue
+		}
+		name, constraint := part[1:], ""
+		if part[0] == ':' {
+			name, constraint = splitParam(part)
+		}
+		parts[i] = "{" + name + "}"
+		params = append(params, map[string]interface{}{
+			"name":     name,
+			"in":       "path",
+			"required": true,
+			"schema":   constraintSchema(constraint),
+		})
+	}
+	return strings.Join(parts, "/"), params
+}
+
+func constraintSchema(constraint string) map[string]interface{} {
+	switch constraint {
+	case "":
+		return map[string]interface{}{"type": "string"}
+	case "int":
+		return map[string]interface{}{"type": "integer"}
+	case "uint":
+		return map[string]interface{}{"type": "integer", "minimum": 0}
+	case "float":
+		return map[string]interface{}{"type": "number"}
+	case "alpha":
+		return map[string]interface{}{"type": "string", "pattern": "^[a-zA-Z]+$"}
+	case "uuid":
+		return map[string]interface{}{"type": "string", "format": "uuid"}
+	}
+	paramTypes.RLock()
+	_, named := paramTypes.checks[constraint]
+	paramTypes.RUnlock()
+	if named {
+		// a custom type registered with RegisterParamType, its check is opaque
+		return map[string]interface{}{"type": "string"}
+	}
+	return map[string]interface{}{"type": "string", "pattern": "^(?:" + constraint + ")$"}
+}
+
+// queryParams documents the fields of a struct as query parameters, named by the
+// query tag or else the json tag
+func (b *specBuilder) queryParams(t reflect.Type) []interface{} {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+	var params []interface{}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.PkgPath != "" {
+			continue
+		}
+		name, omitempty, ok := fieldName(f, "query")
+		if !ok {
+			continue
+		}
+		params = append(params, map[string]interface{}{
+			"name":     name,
+			"in":       "query",
+			"required": !omitempty && f.Type.Kind() != reflect.Ptr,
+			"schema":   b.schema(f.Type),
+		})
+	}
+	return params
+}
+
+// fieldName returns the name of a struct field in the document, from tag or else the json tag
+func fieldName(f reflect.StructField, tag string) (name string, omitempty bool, ok bool) {
+	value, found := f.Tag.Lookup(tag)
+	if !found {
+		value = f.Tag.Get("json")
+	}
+	if value == "-" {
+		return "", false, false
+	}
+	opts := strings.Split(value, ",")
+	name = opts[0]
+	if name == "" {
+		name = f.Name
+	}
+	for _, opt := range opts[1:] {
+		if opt == "omitempty" {
+			omitempty = true
+		}
+	}
+	return name, omitempty, true
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// schema reflects t into a JSON schema, named structs are added to components
+// and referenced by $ref
+func (b *specBuilder) schema(t reflect.Type) map[string]interface{} {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == timeType {
+		return map[string]interface{}{"type": "string", "format": "date-time"}
+	}
+	switch t.Kind() {
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return map[string]interface{}{"type": "integer", "format": "int32"}
+	case reflect.Int64, reflect.Uint64:
+		return map[string]interface{}{"type": "integer", "format": "int64"}
+	case reflect.Float32:
+		return map[string]interface{}{"type": "number", "format": "float"}
+	case reflect.Float64:
+		return map[string]interface{}{"type": "number", "format": "double"}
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return map[string]interface{}{"type": "string", "format": "byte"}
+		}
+		return map[string]interface{}{"type": "array", "items": b.schema(t.Elem())}
+	case reflect.Map:
+		return map[string]interface{}{"type": "object", "additionalProperties": b.schema(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return b.structSchema(t)
+		}
+		_, seen := b.names[t]
+		name := b.schemaName(t)
+		if !seen {
+			// registered before the fields for recursive types
+			b.schemas[name] = nil
+			b.schemas[name] = b.structSchema(t)
+		}
+		return map[string]interface{}{"$ref": "#/components/schemas/" + name}
+	}
+	return map[string]interface{}{}
+}
+
+func (b *specBuilder) structSchema(t reflect.Type) map[string]interface{} {
+	properties := make(map[string]interface{})
+	var required []string
+	b.addFields(t, properties, &required)
+	schema := map[string]interface{}{"type": "object", "properties": properties}
+	if len(required) > 0 {
+		sort.Strings(required)
+		schema["required"] = required
+	}
+	return schema
+}
+
+func (b *specBuilder) addFields(t reflect.Type, properties map[string]interface{}, required *[]string) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		ft := f.Type
+		if f.Anonymous && f.Tag.Get("json") == "" {
+			// embedded structs are flattened as encoding/json does
+			for ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				b.addFields(ft, properties, required)
+				continue
+			}
+		}
+		if f.PkgPath != "" {
+			continue
+		}
+		name, omitempty, ok := fieldName(f, "json")
+		if !ok {
+			continue
+		}
+		properties[name] = b.schema(ft)
+		if !omitempty && ft.Kind() != reflect.Ptr {
+			*required = append(*required, name)
+		}
+	}
+}
+
+// encodeYAML renders a document as YAML, maps are written with sorted keys
+func encodeYAML(doc interface{}) []byte {
+	// normalize the document to maps, slices and scalars
+	data, err := json.Marshal(doc)
+	if err != nil {
+		panic(err)
+	}
+	var value interface{}
+	if err := json.Unmarshal(data, &value); err != nil {
+		panic(err)
+	}
+	var b strings.Builder
+	writeYAML(&b, value, 0)
+	return []byte(b.String())
+}
+
+func writeYAML(b *strings.Builder, value interface{}, indent int) {
+	pad := strings.Repeat("  ", indent)
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if len(v) == 0 {
+			b.WriteString(pad + "{}\n")
+			return
+		}
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			b.WriteString(pad + yamlScalar(key) + ":")
+			writeYAMLValue(b, v[key], indent)
+		}
+	case []interface{}:
+		if len(v) == 0 {
+			b.WriteString(pad + "[]\n")
+			return
+		}
+		for _, item := range v {
+			if !yamlCollection(item) {
+				b.WriteString(pad + "-")
+				writeYAMLValue(b, item, indent)
+				continue
+			}
+			// the first line of a nested collection follows the dash
+			var nested strings.Builder
+			writeYAML(&nested, item, indent+1)
+			b.WriteString(pad + "- " + nested.String()[len(pad)+2:])
+		}
+	default:
+		b.WriteString(pad + yamlScalar(v) + "\n")
+	}
+}
+
+// writeYAMLValue writes the value after a key or a dash
+func writeYAMLValue(b *strings.Builder, value interface{}, indent int) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if len(v) == 0 {
+			b.WriteString(" {}\n")
+			return
+		}
+		b.WriteString("\n")
+		writeYAML(b, v, indent+1)
+	case []interface{}:
+		if len(v) == 0 {
+			b.WriteString(" []\n")
+			return
+		}
+		b.WriteString("\n")
+		writeYAML(b, v, indent+1)
+	default:
+		b.WriteString(" " + yamlScalar(v) + "\n")
+	}
+}
+
+func yamlCollection(value interface{}) bool {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		return len(v) > 0
+	case []interface{}:
+		return len(v) > 0
+	}
+	return false
+}
+
+func yamlScalar(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return strconv.FormatBool(v)
+	case float64:
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	case string:
+		if v == "" || strings.ContainsAny(v, ":#{}[]&*!|>'\"%@`,?\n\\") ||
+			strings.TrimSpace(v) != v || v[0] == '-' || yamlAmbiguous(v) {
+			return strconv.Quote(v)
+		}
+		return v
+	}
+	return fmt.Sprint(value)
+}
+
+// yamlAmbiguous reports if a plain string would be read back as another type
+func yamlAmbiguous(s string) bool {
+	switch strings.ToLower(s) {
+	case "true", "false", "yes", "no", "on", "off", "null", "~", "y", "n":
+		return true
+	}
+	_, err := strconv.ParseFloat(s, 64)
+	return err == nil
+}
@@ -0,0 +1,155 @@
+package tinyGin
+
+import (
+	"encoding/json"
+	htmltemplate "html/template"
+	"net/http"
+	"strings"
+	"testing"
+	texttemplate "text/template"
+	"time"
+)
+
+type apiUser struct {
+	ID      int64     `json:"id"`
+	Name    string    `json:"name"`
+	Email   string    `json:"email,omitempty"`
+	Created time.Time `json:"created"`
+	Friends []apiUser `json:"friends,omitempty"`
+}
+
+type apiPage[T any] struct {
+	Items []T `json:"items"`
+}
+
+type apiListUsers struct {
+	Page  int    `query:"page"`
+	Query string `json:"q,omitempty"`
+}
+
+func newOpenAPIEngine() *Engine {
+	r := New()
+	api := r.Group("/api")
+	api.GET("/users", func(c *Context) {}).
+		Summary("list users").Tags("users").Request(apiListUsers{}).Response(http.StatusOK, []apiUser{})
+	api.GET("/users/:id<int>", func(c *Context) {}).
+		Summary("get a user").Tags("users").Response(http.StatusOK, apiUser{}).Response(http.StatusNotFound, nil)
+	api.POST("/users", func(c *Context) {}).
+		Request(&apiUser{}).Response(http.StatusCreated, &apiUser{})
+	r.GET("/assets/*filepath", func(c *Context) {})
+	return r
+}
+
+func TestEngine_OpenAPI(t *testing.T) {
+	r := newOpenAPIEngine()
+	r.ServeOpenAPI("/openapi.json", OpenAPIInfo{Title: "users", Version: "1.0.0"})
+
+	w := serve(r, "GET", "/openapi.json")
+	if w.Code != http.StatusOK {
+		t.Fatalf("expect 200, but got %d", w.Code)
+	}
+	var doc struct {
+		OpenAPI string
+		Info    OpenAPIInfo
+		Paths   map[string]map[string]struct {
+			Summary     string
+			Tags        []string
+			Parameters  []map[string]interface{}
+			RequestBody map[string]interface{}
+			Responses   map[string]map[string]interface{}
+		}
+		Components struct {
+			Schemas map[string]struct {
+				Properties map[string]map[string]interface{}
+				Required   []string
+			}
+		}
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &doc); err != nil {
+		t.Fatal(err)
+	}
+	if doc.OpenAPI != "3.0.3" || doc.Info.Title != "users" {
+		t.Fatalf("unexpected header %s %v", doc.OpenAPI, doc.Info)
+	}
+	if _, ok := doc.Paths["/openapi.json"]; ok {
+		t.Fatal("expect the document route not to be documented")
+	}
+
+	get := doc.Paths["/api/users/{id}"]["get"]
+	if get.Summary != "get a user" || len(get.Tags) != 1 || get.Tags[0] != "users" {
+		t.Fatalf("unexpected operation %+v", get)
+	}
+	if len(get.Parameters) != 1 || get.Parameters[0]["name"] != "id" || get.Parameters[0]["in"] != "path" ||
+		get.Parameters[0]["schema"].(map[string]interface{})["type"] != "integer" {
+		t.Fatalf("unexpected path params %v", get.Parameters)
+	}
+	if _, ok := get.Responses["404"]["content"]; ok || get.Responses["404"]["description"] != "Not Found" {
+		t.Fatalf("expect 404 without content, but got %v", get.Responses["404"])
+	}
+
+	list := doc.Paths["/api/users"]["get"]
+	if len(list.Parameters) != 2 || list.Parameters[0]["name"] != "page" || list.Parameters[1]["name"] != "q" ||
+		list.Parameters[1]["required"] != false {
+		t.Fatalf("unexpected query params %v", list.Parameters)
+	}
+	if post := doc.Paths["/api/users"]["post"]; post.RequestBody == nil || post.Responses["201"] == nil {
+		t.Fatalf("expect a request body and a 201 response, but got %+v", post)
+	}
+	if _, ok := doc.Paths["/assets/{filepath}"]["get"].Responses["200"]; !ok {
+		t.Fatal("expect a default 200 response for an undocumented route")
+	}
+
+	user := doc.Components.Schemas["tinyGin.apiUser"]
+	if user.Properties["created"]["format"] != "date-time" ||
+		user.Properties["friends"]["items"].(map[string]interface{})["$ref"] != "#/components/schemas/tinyGin.apiUser" {
+		t.Fatalf("unexpected schema %v", user.Properties)
+	}
+	if strings.Join(user.Required, ",") != "created,id,name" {
+		t.Fatalf("expect created,id,name required, but got %v", user.Required)
+	}
+}
+
+func TestEngine_OpenAPI_SchemaNames(t *testing.T) {
+	r := New()
+	r.GET("/page", func(c *Context) {}).Response(http.StatusOK, apiPage[apiUser]{})
+	r.GET("/html", func(c *Context) {}).Response(http.StatusOK, htmltemplate.Template{})
+	r.GET("/text", func(c *Context) {}).Response(http.StatusOK, texttemplate.Template{})
+
+	schemas := r.OpenAPI(OpenAPIInfo{})["components"].(map[string]interface{})["schemas"].(map[string]interface{})
+	for _, name := range []string{"tinyGin.apiPage_tinyGin.apiUser", "tinyGin.apiUser", "template.Template", "template.Template_2"} {
+		if _, ok := schemas[name]; !ok {
+			t.Fatalf("expect schema %s, but got %v", name, keys(schemas))
+		}
+	}
+}
+
+func keys(m map[string]interface{}) []string {
+	var names []string
+	for name := range m {
+		names = append(names, name)
+	}
+	return names
+}
+
+func TestEngine_ServeOpenAPI_YAML(t *testing.T) {
+	r := newOpenAPIEngine()
+	r.ServeOpenAPI("/openapi.yaml", OpenAPIInfo{Title: "users: v1", Version: "1.0"})
+
+	w := serve(r, "GET", "/openapi.yaml")
+	if w.Header().Get("Content-Type") != "application/yaml" {
+		t.Fatalf("expect application/yaml, but got %s", w.Header().Get("Content-Type"))
+	}
+	body := w.Body.String()
+	for _, line := range []string{
+		"openapi: 3.0.3\n",
+		"  title: \"users: v1\"\n",
+		"  version: \"1.0\"\n",
+		"  \"/api/users/{id}\":\n",
+		"      tags:\n        - users\n",
+		"        - in: path\n          name: filepath\n",
+	} {
+		if !strings.Contains(body, line) {
+			t.Fatalf("expect %q in\n%s", line, body)
+		}
+	}
+}
@@ -0,0 +1,136 @@
+package tinyGin
+
+import (
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+)
+
+// cleanPath is path.Clean which keeps the trailing slash, e.g. //a/../b/ => /b/
+func cleanPath(p string) string {
+	if p == "" {
+		return "/"
+	}
+	if p[0] != '/' {
+		p = "/" + p
+	}
+	cleaned := path.Clean(p)
+	if p[len(p)-1] == '/' && cleaned != "/" {
+		cleaned += "/"
+	}
+	return cleaned
+}
+
+func hasTrailingSlash(p string) bool {
+	return len(p) > 1 && p[len(p)-1] == '/'
+}
+
+// slashMismatch reports whether path and pattern differ in the trailing slash,
+// a catch-all pattern accepts both
+func slashMismatch(pattern string, path string) bool {
+	if strings.Contains(pattern, "/*") {
+		return false
+	}
+	return hasTrailingSlash(pattern) != hasTrailingSlash(path)
+}
+
+// toggleSlash adds the trailing slash to path or removes it
+func toggleSlash(path string) string {
+	if hasTrailingSlash(path) {
+		return path[:len(path)-1]
+	}
+	return path + "/"
+}
+
+// searchFold is search which compares static parts case-insensitively
+func (n *node) searchFold(parts []string, height int) *node {
+	if len(parts) == height || strings.HasPrefix(n.part, "*") {
+		if n.pattern == "" {
+			return nil
+		}
+		return n
+	}
+
+	part := parts[height]
+	for _, child := range n.children {
+		if child.isWild {
+			if child.check != nil && !child.check(part) {
+				continue
+			}
+		} else if !strings.EqualFold(child.part, part) {
+			continue
+		}
+		if result := child.searchFold(parts, height+1); result != nil {
+			return result
+		}
+	}
+	return nil
+}
+
+// findFixedPath returns the registered spelling of path, ignoring the case of static parts,
+// the trailing slash is only fixed if fixTrailingSlash is true
+func (r *router) findFixedPath(method string, path string, fixTrailingSlash bool) (string, bool) {
+	root, ok := r.roots[method]
+	if !ok {
+		return "", false
+	}
+	searchParts := parsePattern(path)
+	n := root.searchFold(searchParts, 0)
+	if n == nil {
+		return "", false
+	}
+	pattern := n.patternFor(path)
+	if !fixTrailingSlash && slashMismatch(pattern, path) {
+		return "", false
+	}
+
+	fixed := make([]string, 0, len(searchParts))
+	for index, part := range parsePattern(pattern) {
+		if part[0] == '*' {
+			fixed = append(fixed, searchParts[index:]...)
+			break
+		}
+		if part[0] == ':' {
+			fixed = append(fixed, searchParts[index])
+		} else {
+			fixed = append(fixed, part)
+		}
+	}
+	result := "/" + strings.Join(fixed, "/")
+	if hasTrailingSlash(pattern) || (hasTrailingSlash(path) && strings.Contains(pattern, "/*")) {
+		result += "/"
+	}
+	return result, true
+}
+
+// requestPath returns the path used for routing
+func (engine *Engine) requestPath(req *http.Request) string {
+	if engine.UseRawPath && req.URL.RawPath != "" {
+		return req.URL.RawPath
+	}
+	return req.URL.Path
+}
+
+// unescapeParams unescapes the params captured from a raw path
+func unescapeParams(params map[string]string) {
+	for key, value := range params {
+		if unescaped, err := url.PathUnescape(value); err == nil {
+			params[key] = unescaped
+		}
+	}
+}
+
+// redirect answers 301 for GET and 308 for the other methods,
+// so the method and body are kept by the client
+func redirect(c *Context, location string) {
+	code := http.StatusMovedPermanently
+	if c.Method != http.MethodGet {
+		code = http.StatusPermanentRedirect
+	}
+	if c.Req.URL.RawQuery != "" {
+		location += "?" + c.Req.URL.RawQuery
+	}
+	http.Redirect(c.Writer, c.Req, location, code)
+	c.StatusCode = code
+}
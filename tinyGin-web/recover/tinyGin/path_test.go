@@ -0,0 +1,99 @@
+package tinyGin
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCleanPath(t *testing.T) {
+	cases := map[string]string{
+		"":                 "/",
+		"hello":            "/hello",
+		"//hello/../hello": "/hello",
+		"/a/./b/":          "/a/b/",
+		"/../":             "/",
+	}
+	for p, expect := range cases {
+		if cleaned := cleanPath(p); cleaned != expect {
+			t.Fatalf("cleanPath(%q): expect %q, but got %q", p, expect, cleaned)
+		}
+	}
+}
+
+func serve(r *Engine, method string, target string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(method, target, nil))
+	return w
+}
+
+func TestEngine_Redirect(t *testing.T) {
+	r := New()
+	r.RedirectFixedPath = true
+	r.GET("/hello", func(c *Context) { c.String(http.StatusOK, "hello") })
+	r.POST("/users/", func(c *Context) { c.String(http.StatusOK, "users") })
+	r.GET("/Docs/:name", func(c *Context) { c.String(http.StatusOK, "%s", c.Param("name")) })
+
+	cases := []struct {
+		method   string
+		target   string
+		code     int
+		location string
+	}{
+		{"GET", "/hello", http.StatusOK, ""},
+		{"GET", "/hello/?a=1", http.StatusMovedPermanently, "/hello?a=1"},
+		{"POST", "/users", http.StatusPermanentRedirect, "/users/"},
+		{"GET", "//hello/../hello", http.StatusMovedPermanently, "/hello"},
+		{"GET", "/HELLO", http.StatusMovedPermanently, "/hello"},
+		{"GET", "/docs/Intro", http.StatusMovedPermanently, "/Docs/Intro"},
+		{"GET", "/nothing", http.StatusNotFound, ""},
+	}
+	for _, tc := range cases {
+		w := serve(r, tc.method, tc.target)
+		if w.Code != tc.code || w.Header().Get("Location") != tc.location {
+			t.Fatalf("%s %s: expect %d %q, but got %d %q",
+				tc.method, tc.target, tc.code, tc.location, w.Code, w.Header().Get("Location"))
+		}
+	}
+
+	r.RedirectTrailingSlash = false
+	if w := serve(r, "GET", "/hello/"); w.Code != http.StatusOK {
+		t.Fatalf("expect /hello/ to be served by /hello without RedirectTrailingSlash, but got %d", w.Code)
+	}
+}
+
+func TestEngine_TrailingSlashRoutes(t *testing.T) {
+	r := New()
+	r.GET("/foo", func(c *Context) { c.String(http.StatusOK, "foo %s", c.FullPath()) })
+	r.GET("/foo/", func(c *Context) { c.String(http.StatusOK, "foo/ %s", c.FullPath()) })
+
+	for target, body := range map[string]string{"/foo": "foo /foo", "/foo/": "foo/ /foo/"} {
+		if w := serve(r, "GET", target); w.Code != http.StatusOK || w.Body.String() != body {
+			t.Fatalf("%s: expect %q, but got %d %q", target, body, w.Code, w.Body.String())
+		}
+	}
+
+	if !r.RemoveRoute("GET", "/foo/") {
+		t.Fatal("expect /foo/ to be removed")
+	}
+	if w := serve(r, "GET", "/foo"); w.Body.String() != "foo /foo" {
+		t.Fatalf("expect /foo to be kept, but got %d %q", w.Code, w.Body.String())
+	}
+	if w := serve(r, "GET", "/foo/"); w.Code != http.StatusMovedPermanently || w.Header().Get("Location") != "/foo" {
+		t.Fatalf("expect /foo/ to redirect to /foo, but got %d", w.Code)
+	}
+}
+
+func TestEngine_UseRawPath(t *testing.T) {
+	r := New()
+	r.UseRawPath = true
+	r.GET("/files/:name", func(c *Context) { c.String(http.StatusOK, "%s", c.Param("name")) })
+
+	if w := serve(r, "GET", "/files/a%2Fb"); w.Code != http.StatusOK || w.Body.String() != "a/b" {
+		t.Fatalf("expect a/b, but got %d %q", w.Code, w.Body.String())
+	}
+	r.UnescapePathValues = false
+	if w := serve(r, "GET", "/files/a%2Fb"); w.Body.String() != "a%2Fb" {
+		t.Fatalf("expect a%%2Fb, but got %q", w.Body.String())
+	}
+}
@@ -0,0 +1,106 @@
+package proxy
+
+import (
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Balancer selects the upstream of a request
+type Balancer int
+
+const (
+	// RoundRobin selects the upstreams in turn
+	RoundRobin Balancer = iota
+	// LeastConn selects the upstream with the fewest active requests
+	LeastConn
+)
+
+type upstream struct {
+	active int64 // accessed atomically, keep it first for 64 bit alignment
+	target *url.URL
+	proxy  *httputil.ReverseProxy
+
+	mu        sync.Mutex
+	fails     int
+	downUntil time.Time
+}
+
+// available reports whether the upstream is not marked down by the passive health check
+func (u *upstream) available(now time.Time) bool {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	return !now.Before(u.downUntil)
+}
+
+// serve proxies req to the upstream, it's counted active until the proxy returns or panics,
+// e.g. with http.ErrAbortHandler when the client goes away
+func (u *upstream) serve(w http.ResponseWriter, req *http.Request) {
+	atomic.AddInt64(&u.active, 1)
+	defer atomic.AddInt64(&u.active, -1)
+	u.proxy.ServeHTTP(w, req)
+}
+
+type pool struct {
+	next        uint64 // accessed atomically, keep it first for 64 bit alignment
+	upstreams   []*upstream
+	balancer    Balancer
+	maxFails    int
+	failTimeout time.Duration
+}
+
+// pick returns an available upstream not in tried, nil if there is none.
+// When every upstream is down, the down ones are tried rather than failing at once
+func (p *pool) pick(tried map[*upstream]bool) *upstream {
+	now := time.Now()
+	var candidates, down []*upstream
+	for _, u := range p.upstreams {
+		if tried[u] {
+			continue
+		}
+		if u.available(now) {
+			candidates = append(candidates, u)
+		} else {
+			down = append(down, u)
+		}
+	}
+	if len(candidates) == 0 {
+		candidates = down
+	}
+	if len(candidates) == 0 {
+		return nil
+	}
+
+	if p.balancer == LeastConn {
+		best := candidates[0]
+		for _, u := range candidates[1:] {
+			if atomic.LoadInt64(&u.active) < atomic.LoadInt64(&best.active) {
+				best = u
+			}
+		}
+		return best
+	}
+	i := atomic.AddUint64(&p.next, 1) - 1
+	return candidates[i%uint64(len(candidates))]
+}
+
+// success resets the failures of u
+func (p *pool) success(u *upstream) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.fails = 0
+}
+
+// failure marks u down for failTimeout after maxFails consecutive failures
+func (p *pool) failure(u *upstream) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.fails++
+	if u.fails >= p.maxFails {
+		u.fails = 0
+		u.downUntil = time.Now().Add(p.failTimeout)
+	}
+}
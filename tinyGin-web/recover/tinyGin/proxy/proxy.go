@@ -0,0 +1,262 @@
+package proxy
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"strings"
+	"time"
+
+	"tinyGin"
+)
+
+// Options configures a reverse proxy, zero values fall back to the defaults
+type Options struct {
+	// Targets are the upstream base urls, e.g. http://10.0.0.1:8080/api
+	Targets  []string
+	Balancer Balancer
+
+	// StripPrefix is removed from the request path before Rewrite
+	StripPrefix string
+	// Rewrite returns the path sent upstream, it is joined to the path of the target
+	Rewrite func(path string) string
+
+	// RequestHeaders are set on the upstream request, an empty value removes the header
+	RequestHeaders map[string]string
+	// ResponseHeaders are set on the response, an empty value removes the header
+	ResponseHeaders map[string]string
+
+	// Retries is the number of other upstreams tried when an idempotent request fails to connect
+	Retries int
+	// MaxRetryBody is the largest request body buffered for retries, default 1MB.
+	// A larger body is streamed to a single upstream without retries
+	MaxRetryBody int64
+	// MaxFails consecutive failures mark an upstream down for FailTimeout, default 3 and 10s
+	MaxFails    int
+	FailTimeout time.Duration
+
+	// Transport is used for upstream requests, default http.DefaultTransport
+	Transport http.RoundTripper
+}
+
+var idempotentMethods = map[string]bool{
+	http.MethodGet:     true,
+	http.MethodHead:    true,
+	http.MethodOptions: true,
+	http.MethodTrace:   true,
+	http.MethodPut:     true,
+	http.MethodDelete:  true,
+}
+
+type errKey struct{}
+
+// attempt records the error of one upstream round trip
+type attempt struct {
+	err error
+}
+
+// New returns a HandlerFunc proxying requests to the upstream pool of opts,
+// WebSocket upgrades are passed through
+func New(opts Options) (tinyGin.HandlerFunc, error) {
+	if len(opts.Targets) == 0 {
+		return nil, errors.New("proxy: no target")
+	}
+	if opts.MaxFails <= 0 {
+		opts.MaxFails = 3
+	}
+	if opts.FailTimeout <= 0 {
+		opts.FailTimeout = 10 * time.Second
+	}
+	if opts.MaxRetryBody <= 0 {
+		opts.MaxRetryBody = 1 << 20
+	}
+
+	p := &pool{balancer: opts.Balancer, maxFails: opts.MaxFails, failTimeout: opts.FailTimeout}
+	for _, target := range opts.Targets {
+		u, err := url.Parse(target)
+		if err != nil {
+			return nil, err
+		}
+		if u.Scheme == "" || u.Host == "" {
+			return nil, errors.New("proxy: bad target " + target)
+		}
+		p.upstreams = append(p.upstreams, &upstream{target: u, proxy: opts.newReverseProxy(u)})
+	}
+
+	return func(c *tinyGin.Context) {
+		path := strings.TrimPrefix(c.Req.URL.Path, opts.StripPrefix)
+		if opts.Rewrite != nil {
+			path = opts.Rewrite(path)
+		}
+
+		attempts := 1
+		var body []byte
+		if idempotentMethods[c.Method] && opts.Retries > 0 {
+			attempts += opts.Retries
+			// the body is read again by every attempt
+			if c.Req.Body != nil && c.Req.Body != http.NoBody {
+				var err error
+				if body, attempts, err = opts.bufferBody(c.Req, attempts); err != nil {
+					c.Fail(http.StatusBadRequest, err.Error())
+					return
+				}
+			}
+		}
+
+		tried := make(map[*upstream]bool)
+		for i := 0; i < attempts; i++ {
+			u := p.pick(tried)
+			if u == nil {
+				break
+			}
+			tried[u] = true
+
+			a := &attempt{}
+			req := c.Req.Clone(context.WithValue(c.Req.Context(), errKey{}, a))
+			req.URL.Path, req.URL.RawPath = path, ""
+			if body != nil {
+				req.Body = ioutil.NopCloser(bytes.NewReader(body))
+			}
+			w := &responseWriter{ResponseWriter: c.Writer}
+
+			u.serve(w, req)
+
+			if a.err == nil {
+				p.success(u)
+				c.StatusCode = w.status
+				return
+			}
+			p.failure(u)
+			if w.status != 0 {
+				// the response has been started, it can't be retried
+				c.StatusCode = w.status
+				return
+			}
+		}
+		c.Fail(http.StatusBadGateway, "Bad Gateway")
+	}, nil
+}
+
+// bufferBody reads the body of req for retries, up to MaxRetryBody. A larger body is
+// put back in front of the rest of req.Body and the request gets a single attempt
+func (opts *Options) bufferBody(req *http.Request, attempts int) ([]byte, int, error) {
+	if req.ContentLength > opts.MaxRetryBody {
+		return nil, 1, nil
+	}
+	body, err := ioutil.ReadAll(io.LimitReader(req.Body, opts.MaxRetryBody+1))
+	if err != nil {
+		return nil, 0, err
+	}
+	if int64(len(body)) > opts.MaxRetryBody {
+		req.Body = struct {
+			io.Reader
+			io.Closer
+		}{io.MultiReader(bytes.NewReader(body), req.Body), req.Body}
+		return nil, 1, nil
+	}
+	return body, attempts, nil
+}
+
+func (opts *Options) newReverseProxy(target *url.URL) *httputil.ReverseProxy {
+	return &httputil.ReverseProxy{
+		Director: func(req *http.Request) {
+			req.Header.Set("X-Forwarded-Host", req.Host)
+			req.Header.Set("X-Forwarded-Proto", scheme(req))
+			req.URL.Scheme = target.Scheme
+			req.URL.Host = target.Host
+			req.URL.Path = singleJoiningSlash(target.Path, req.URL.Path)
+			if target.RawQuery == "" || req.URL.RawQuery == "" {
+				req.URL.RawQuery = target.RawQuery + req.URL.RawQuery
+			} else {
+				req.URL.RawQuery = target.RawQuery + "&" + req.URL.RawQuery
+			}
+			req.Host = target.Host
+			setHeaders(req.Header, opts.RequestHeaders)
+		},
+		Transport: opts.Transport,
+		ModifyResponse: func(res *http.Response) error {
+			setHeaders(res.Header, opts.ResponseHeaders)
+			return nil
+		},
+		ErrorHandler: func(w http.ResponseWriter, req *http.Request, err error) {
+			// the handler answers once all attempts failed
+			req.Context().Value(errKey{}).(*attempt).err = err
+		},
+	}
+}
+
+func setHeaders(header http.Header, values map[string]string) {
+	for key, value := range values {
+		if value == "" {
+			header.Del(key)
+		} else {
+			header.Set(key, value)
+		}
+	}
+}
+
+func scheme(req *http.Request) string {
+	if req.TLS != nil {
+		return "https"
+	}
+	return "http"
+}
+
+func singleJoiningSlash(a, b string) string {
+	aslash := strings.HasSuffix(a, "/")
+	bslash := strings.HasPrefix(b, "/")
+	switch {
+	case aslash && bslash:
+		return a + b[1:]
+	case !aslash && !bslash:
+		return a + "/" + b
+	}
+	return a + b
+}
+
+// responseWriter records whether the response has been started,
+// it keeps Flusher and Hijacker for streaming and WebSocket passthrough
+type responseWriter struct {
+	http.ResponseWriter
+	status int
+}
+
+func (w *responseWriter) WriteHeader(code int) {
+	if w.status == 0 {
+		w.status = code
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *responseWriter) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *responseWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+func (w *responseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	h, ok := w.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, errors.New("proxy: response does not implement http.Hijacker")
+	}
+	w.status = http.StatusSwitchingProtocols
+	return h.Hijack()
+}
+
+func (w *responseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
@@ -0,0 +1,170 @@
+package proxy
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/http/httputil"
+	"strings"
+	"testing"
+	"time"
+
+	"tinyGin"
+	"tinyGin/ws"
+)
+
+func newUpstream(name string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("X-Internal", "secret")
+		fmt.Fprintf(w, "%s %s %s", name, req.URL.Path, req.Header.Get("X-Tenant"))
+	}))
+}
+
+func newProxyEngine(t *testing.T, opts Options) *tinyGin.Engine {
+	t.Helper()
+	handler, err := New(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := tinyGin.New()
+	r.GET("/api/*path", handler)
+	r.POST("/api/*path", handler)
+	return r
+}
+
+func get(r *tinyGin.Engine, method string, path string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(method, path, strings.NewReader("body")))
+	return w
+}
+
+func TestProxy_RoundRobin(t *testing.T) {
+	a, b := newUpstream("a"), newUpstream("b")
+	defer a.Close()
+	defer b.Close()
+	r := newProxyEngine(t, Options{
+		Targets:         []string{a.URL + "/v1", b.URL + "/v1"},
+		StripPrefix:     "/api",
+		RequestHeaders:  map[string]string{"X-Tenant": "tiny"},
+		ResponseHeaders: map[string]string{"X-Internal": ""},
+	})
+
+	var bodies []string
+	for i := 0; i < 4; i++ {
+		w := get(r, "GET", "/api/users")
+		if w.Header().Get("X-Internal") != "" {
+			t.Fatal("expect X-Internal to be removed")
+		}
+		bodies = append(bodies, w.Body.String())
+	}
+	expect := "a /v1/users tiny,b /v1/users tiny,a /v1/users tiny,b /v1/users tiny"
+	if strings.Join(bodies, ",") != expect {
+		t.Fatalf("unexpected bodies %v", bodies)
+	}
+}
+
+func TestProxy_Retry(t *testing.T) {
+	alive, dead := newUpstream("alive"), newUpstream("dead")
+	defer alive.Close()
+	dead.Close()
+	r := newProxyEngine(t, Options{
+		Targets:  []string{dead.URL, alive.URL},
+		Retries:  1,
+		MaxFails: 1,
+	})
+
+	for i := 0; i < 3; i++ {
+		if w := get(r, "GET", "/api/users"); w.Code != http.StatusOK || !strings.HasPrefix(w.Body.String(), "alive") {
+			t.Fatalf("expect the alive upstream, but got %d %q", w.Code, w.Body.String())
+		}
+	}
+	// POST is not idempotent, the dead upstream is down so the alive one is picked
+	if w := get(r, "POST", "/api/users"); w.Code != http.StatusOK {
+		t.Fatalf("expect the dead upstream to be skipped, but got %d", w.Code)
+	}
+}
+
+func TestProxy_BadGateway(t *testing.T) {
+	dead := newUpstream("dead")
+	dead.Close()
+	r := newProxyEngine(t, Options{Targets: []string{dead.URL}})
+	if w := get(r, "GET", "/api/users"); w.Code != http.StatusBadGateway {
+		t.Fatalf("expect 502, but got %d", w.Code)
+	}
+}
+
+func TestPool_LeastConn(t *testing.T) {
+	a, b := &upstream{}, &upstream{}
+	a.active = 2
+	p := &pool{upstreams: []*upstream{a, b}, balancer: LeastConn, maxFails: 1, failTimeout: time.Minute}
+	if p.pick(nil) != b {
+		t.Fatal("expect the upstream with fewer connections")
+	}
+	p.failure(b)
+	if p.pick(nil) != a {
+		t.Fatal("expect the down upstream to be skipped")
+	}
+}
+
+func TestUpstream_ServePanic(t *testing.T) {
+	u := &upstream{proxy: &httputil.ReverseProxy{Director: func(*http.Request) {}, Transport: panicTransport{}}}
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Fatal("expect the panic to propagate")
+			}
+		}()
+		u.serve(httptest.NewRecorder(), httptest.NewRequest("GET", "http://upstream/", nil))
+	}()
+	if u.active != 0 {
+		t.Fatalf("expect no active request after a panic, but got %d", u.active)
+	}
+}
+
+type panicTransport struct{}
+
+func (panicTransport) RoundTrip(*http.Request) (*http.Response, error) {
+	panic(http.ErrAbortHandler)
+}
+
+func TestProxy_WebSocket(t *testing.T) {
+	backend := tinyGin.New()
+	backend.WS("/chat", func(c *tinyGin.Context, conn *ws.Conn) {
+		_, data, _ := conn.ReadMessage()
+		_ = conn.WriteMessage(ws.TextMessage, append([]byte("echo "), data...))
+	})
+	upstream := httptest.NewServer(backend)
+	defer upstream.Close()
+
+	handler, _ := New(Options{Targets: []string{upstream.URL}})
+	r := tinyGin.New()
+	r.GET("/chat", handler)
+	front := httptest.NewServer(r)
+	defer front.Close()
+
+	conn, _, err := ws.Dial("ws"+strings.TrimPrefix(front.URL, "http")+"/chat", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	_ = conn.WriteMessage(ws.TextMessage, []byte("hi"))
+	if _, data, err := conn.ReadMessage(); err != nil || string(data) != "echo hi" {
+		t.Fatalf("unexpected reply %q %v", data, err)
+	}
+}
+
+func TestProxy_RetryBodyTooLarge(t *testing.T) {
+	alive, dead := newUpstream("alive"), newUpstream("dead")
+	defer alive.Close()
+	dead.Close()
+	r := newProxyEngine(t, Options{
+		Targets:      []string{dead.URL, alive.URL},
+		Retries:      1,
+		MaxRetryBody: 2,
+	})
+
+	// the body is larger than MaxRetryBody, so the dead upstream picked first is not retried
+	if w := get(r, "GET", "/api/users"); w.Code != http.StatusBadGateway {
+		t.Fatalf("expect no retry for a large body, but got %d", w.Code)
+	}
+}
@@ -2,7 +2,6 @@ package tinyGin
 
 import (
 	"fmt"
-	"log"
 	"net/http"
 	"runtime"
 	"strings"
@@ -23,12 +22,17 @@ func trace(message string) string {
 	return str.String()
 }
 
+// Recovery answers 500 when a handler panics, the stack trace is logged in DebugMode
 func Recovery() HandlerFunc {
 	return func(c *Context) {
 		defer func() {
 			if err := recover(); err != nil {
 				message := fmt.Sprintf("%s", err)
-				log.Printf("%s\n\n", trace(message))
+				if IsDebugging() {
+					errorPrint("%s\n\n", trace(message))
+				} else {
+					errorPrint("panic recovered: %s %s: %s", c.Method, c.Path, message)
+				}
 				c.Fail(http.StatusInternalServerError, "Internal Server Error")
 			}
 		}()
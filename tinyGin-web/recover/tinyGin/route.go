@@ -0,0 +1,120 @@
+package tinyGin
+
+import (
+	"sort"
+	"sync"
+	"sync/atomic"
+)
+
+// Route describes a registered route, the documentation methods can be chained
+// after registration, e.g. r.GET("/users/:id", h).Summary("get a user").Response(200, User{})
+type Route struct {
+	Method  string
+	Pattern string
+
+	mu       sync.Mutex   // serializes writers of info
+	info     atomic.Value // *routeInfo, replaced as a whole since the route may be served already
+	handlers []HandlerFunc
+}
+
+// routeInfo is the metadata and documentation of a Route, it is never modified once stored
+type routeInfo struct {
+	summary     string
+	description string
+	tags        []string
+	request     interface{}
+	responses   map[int]interface{}
+	meta        map[string]interface{}
+}
+
+func (route *Route) load() *routeInfo {
+	if info, ok := route.info.Load().(*routeInfo); ok {
+		return info
+	}
+	return &routeInfo{}
+}
+
+// update applies fn to a copy of the route info and stores the copy
+func (route *Route) update(fn func(info *routeInfo)) *Route {
+	route.mu.Lock()
+	defer route.mu.Unlock()
+	old := route.load()
+	info := *old
+	info.tags = append([]string(nil), old.tags...)
+	info.responses = make(map[int]interface{}, len(old.responses))
+	for code, sample := range old.responses {
+		info.responses[code] = sample
+	}
+	info.meta = make(map[string]interface{}, len(old.meta))
+	for key, value := range old.meta {
+		info.meta[key] = value
+	}
+	fn(&info)
+	route.info.Store(&info)
+	return route
+}
+
+// Set attaches metadata to the route, which handlers read by c.Route().Get(key)
+func (route *Route) Set(key string, value interface{}) *Route {
+	return route.update(func(info *routeInfo) {
+		info.meta[key] = value
+	})
+}
+
+// Get returns the metadata of key
+func (route *Route) Get(key string) (value interface{}, exists bool) {
+	value, exists = route.load().meta[key]
+	return
+}
+
+// Summary sets the short summary of the route
+func (route *Route) Summary(summary string) *Route {
+	return route.update(func(info *routeInfo) {
+		info.summary = summary
+	})
+}
+
+// Description sets the long description of the route
+func (route *Route) Description(description string) *Route {
+	return route.update(func(info *routeInfo) {
+		info.description = description
+	})
+}
+
+// Tags groups the route in the API document
+func (route *Route) Tags(tags ...string) *Route {
+	return route.update(func(info *routeInfo) {
+		info.tags = append(info.tags, tags...)
+	})
+}
+
+// Request sets a value of the request type, the body for POST, PUT and PATCH,
+// otherwise the query parameters
+func (route *Route) Request(sample interface{}) *Route {
+	return route.update(func(info *routeInfo) {
+		info.request = sample
+	})
+}
+
+// Response sets a value of the response type for status code, nil means no content
+func (route *Route) Response(code int, sample interface{}) *Route {
+	return route.update(func(info *routeInfo) {
+		info.responses[code] = sample
+	})
+}
+
+// Routes returns the routes of the default host sorted by pattern and method
+func (engine *Engine) Routes() []*Route {
+	r := engine.router.load()
+	routes := make([]*Route, 0, len(r.routes))
+	for _, route := range r.routes {
+		routes = append(routes, route)
+	}
+	sort.Slice(routes, func(i, j int) bool {
+		if routes[i].Pattern != routes[j].Pattern {
+			return routes[i].Pattern < routes[j].Pattern
+		}
+		return routes[i].Method < routes[j].Method
+	})
+	return routes
+}
@@ -3,17 +3,58 @@ package tinyGin
 import (
 	"net/http"
 	"strings"
+	"sync"
+	"sync/atomic"
 )
 
+// router is a snapshot of the routes, it is never modified once published by routeTable
 type router struct {
-	roots    map[string]*node
-	handlers map[string]HandlerFunc
+	roots  map[string]*node
+	routes map[string]*Route
+}
+
+// routeTable publishes router snapshots in copy-on-write fashion: a route is added to or
+// removed from a copy which then replaces the current snapshot atomically, so routes can
+// change while requests are served
+type routeTable struct {
+	mu      sync.Mutex   // serializes writers
+	current atomic.Value // *router
+}
+
+func newRouteTable() *routeTable {
+	t := &routeTable{}
+	t.current.Store(newRouter())
+	return t
+}
+
+func (t *routeTable) load() *router {
+	return t.current.Load().(*router)
+}
+
+func (t *routeTable) addRoute(method string, pattern string, handlers ...HandlerFunc) *Route {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	r := t.load().clone()
+	route := r.addRoute(method, pattern, handlers...)
+	t.current.Store(r)
+	return route
+}
+
+func (t *routeTable) removeRoute(method string, pattern string) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	r := t.load().clone()
+	if !r.removeRoute(method, pattern) {
+		return false
+	}
+	t.current.Store(r)
+	return true
 }
 
 func newRouter() *router {
 	return &router{
-		roots:    make(map[string]*node),
-		handlers: make(map[string]HandlerFunc),
+		roots:  make(map[string]*node),
+		routes: make(map[string]*Route),
 	}
 }
 
@@ -33,7 +74,7 @@ func parsePattern(pattern string) []string {
 	return parts
 }
 
-func (r *router) addRoute(method string, pattern string, handler HandlerFunc) {
+func (r *router) addRoute(method string, pattern string, handlers ...HandlerFunc) *Route {
 	parts := parsePattern(pattern)
 
 	key := method + "-" + pattern
@@ -42,7 +83,31 @@ func (r *router) addRoute(method string, pattern string, handler HandlerFunc) {
 		r.roots[method] = &node{}
 	}
 	r.roots[method].insert(pattern, parts, 0)
-	r.handlers[key] = handler
+	route := &Route{Method: method, Pattern: pattern, handlers: handlers}
+	r.routes[key] = route
+	return route
+}
+
+// clone copies the tries and routes, so the copy can be modified
+func (r *router) clone() *router {
+	c := newRouter()
+	for method, root := range r.roots {
+		c.roots[method] = root.clone()
+	}
+	for key, route := range r.routes {
+		c.routes[key] = route
+	}
+	return c
+}
+
+func (r *router) removeRoute(method string, pattern string) bool {
+	key := method + "-" + pattern
+	if _, ok := r.routes[key]; !ok {
+		return false
+	}
+	delete(r.routes, key)
+	r.roots[method].remove(pattern, parsePattern(pattern), 0)
+	return true
 }
 
 func (r *router) getRoute(method string, path string) (*node, map[string]string) {
@@ -60,7 +125,8 @@ func (r *router) getRoute(method string, path string) (*node, map[string]string)
 		parts := parsePattern(n.pattern)
 		for index, part := range parts {
 			if part[0] == ':' {
-				params[part[1:]] = searchParts[index]
+				name, _ := splitParam(part)
+				params[name] = searchParts[index]
 			}
 			if part[0] == '*' && len(part) > 1 {
 				params[part[1:]] = strings.Join(searchParts[index:], "/")
@@ -84,12 +150,47 @@ func (r *router) getRoutes(method string) []*node {
 }
 
 func (r *router) handle(c *Context) {
-	n, params := r.getRoute(c.Method, c.Path)
+	engine := c.engine
+	path := engine.requestPath(c.Req)
+	var n *node
+	var params map[string]string
+	// with RedirectFixedPath a path which is not clean never matches directly
+	if !engine.RedirectFixedPath || cleanPath(path) == path {
+		n, params = r.getRoute(c.Method, path)
+	}
+
+	var pattern string
+	if n != nil {
+		pattern = n.patternFor(path)
+	}
+
+	// without RedirectTrailingSlash the route is served as is, like /foo/ matching /foo
+	if n != nil && slashMismatch(pattern, path) && engine.RedirectTrailingSlash && c.Method != http.MethodConnect {
+		r.redirectTo(c, cleanPath(toggleSlash(path)))
+		return
+	}
+	if n == nil && engine.RedirectFixedPath && c.Method != http.MethodConnect {
+		if fixed, ok := r.findFixedPath(c.Method, cleanPath(path), engine.RedirectTrailingSlash); ok && fixed != path {
+			r.redirectTo(c, fixed)
+			return
+		}
+	}
 
 	if n != nil {
-		key := c.Method + "-" + n.pattern
+		if engine.UseRawPath && engine.UnescapePathValues {
+			unescapeParams(params)
+		}
+		key := c.Method + "-" + pattern
+		// params captured from the host
+		for k, v := range c.Params {
+			if _, ok := params[k]; !ok {
+				params[k] = v
+			}
+		}
 		c.Params = params
-		c.handlers = append(c.handlers, r.handlers[key])
+		c.fullPath = pattern
+		c.route = r.routes[key]
+		c.handlers = append(c.handlers, c.route.handlers...)
 	} else {
 		c.handlers = append(c.handlers, func(c *Context) {
 			c.String(http.StatusNotFound, "404 NOT FOUND: %s\n", c.Path)
@@ -97,3 +198,11 @@ func (r *router) handle(c *Context) {
 	}
 	c.Next()
 }
+
+// redirectTo runs the middlewares and then redirects to location
+func (r *router) redirectTo(c *Context, location string) {
+	c.handlers = append(c.handlers, func(c *Context) {
+		redirect(c, location)
+	})
+	c.Next()
+}
@@ -0,0 +1,207 @@
+package tinyGin
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+)
+
+func newTestRouter() *router {
+	r := newRouter()
+	r.addRoute("GET", "/", nil)
+	r.addRoute("GET", "/hello/:name", nil)
+	r.addRoute("GET", "/hello/b/c", nil)
+	r.addRoute("GET", "/user/:id<int>", nil)
+	r.addRoute("GET", "/user/:uuid<uuid>/profile", nil)
+	r.addRoute("GET", "/user/:name", nil)
+	r.addRoute("GET", "/post/:slug<[a-z0-9-]+>", nil)
+	r.addRoute("GET", "/assets/*filepath", nil)
+	return r
+}
+
+func TestGetRoute(t *testing.T) {
+	r := newTestRouter()
+	cases := []struct {
+		path    string
+		pattern string
+		key     string
+		value   string
+	}{
+		{"/hello/tiny", "/hello/:name", "name", "tiny"},
+		{"/hello/b/c", "/hello/b/c", "", ""},
+		{"/user/42", "/user/:id<int>", "id", "42"},
+		{"/user/tom", "/user/:name", "name", "tom"},
+		{"/user/0d5f3d7e-2c3a-4b7f-9a51-8f1f6c2b9e10/profile", "/user/:uuid<uuid>/profile", "uuid", "0d5f3d7e-2c3a-4b7f-9a51-8f1f6c2b9e10"},
+		{"/post/hello-tiny-gin", "/post/:slug<[a-z0-9-]+>", "slug", "hello-tiny-gin"},
+		{"/assets/css/tiny.css", "/assets/*filepath", "filepath", "css/tiny.css"},
+	}
+	for _, tc := range cases {
+		n, params := r.getRoute("GET", tc.path)
+		if n == nil || n.pattern != tc.pattern {
+			t.Fatalf("%s: expect pattern %s, but got %v", tc.path, tc.pattern, n)
+		}
+		if tc.key != "" && params[tc.key] != tc.value {
+			t.Fatalf("%s: expect %s=%s, but got %v", tc.path, tc.key, tc.value, params)
+		}
+	}
+
+	for _, path := range []string{"/post/Hello_World", "/user/tom/profile", "/nothing"} {
+		if n, _ := r.getRoute("GET", path); n != nil {
+			t.Fatalf("%s: expect no route, but got %s", path, n.pattern)
+		}
+	}
+}
+
+func TestContext_ParamInt(t *testing.T) {
+	c := &Context{Params: map[string]string{"id": "42", "name": "tom"}}
+	if id, err := c.ParamInt("id"); err != nil || id != 42 {
+		t.Fatalf("expect 42, but got %d %v", id, err)
+	}
+	if _, err := c.ParamInt("name"); err == nil {
+		t.Fatal("expect an error for a non int param")
+	}
+}
+
+func TestRouterGroup_RemoveRoute(t *testing.T) {
+	r := New()
+	api := r.Group("/api")
+	api.GET("/users/:id", func(c *Context) { c.String(http.StatusOK, "user") })
+	api.GET("/users/:id/posts", func(c *Context) { c.String(http.StatusOK, "posts") })
+
+	if !api.RemoveRoute("GET", "/users/:id") {
+		t.Fatal("expect the route to be removed")
+	}
+	if api.RemoveRoute("GET", "/users/:id") {
+		t.Fatal("expect a removed route not to be removed again")
+	}
+	if w := serve(r, "GET", "/api/users/1"); w.Code != http.StatusNotFound {
+		t.Fatalf("expect 404 for a removed route, but got %d", w.Code)
+	}
+	if w := serve(r, "GET", "/api/users/1/posts"); w.Code != http.StatusOK {
+		t.Fatalf("expect the other route to be kept, but got %d", w.Code)
+	}
+}
+
+// run with -race, routes are added and removed while requests are served
+func TestRouteTable_Concurrent(t *testing.T) {
+	r := New()
+	r.GET("/static", func(c *Context) { c.String(http.StatusOK, "static") })
+
+	var wg sync.WaitGroup
+	stop := make(chan struct{})
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+				}
+				if w := serve(r, "GET", "/static"); w.Code != http.StatusOK {
+					t.Errorf("expect /static to be served while routes change, but got %d", w.Code)
+					return
+				}
+				serve(r, "GET", "/plugin/3")
+			}
+		}()
+	}
+
+	for i := 0; i < 200; i++ {
+		pattern := fmt.Sprintf("/plugin/%d", i%10)
+		r.GET(pattern, func(c *Context) { c.String(http.StatusOK, "plugin") })
+		if i%3 == 0 {
+			r.RemoveRoute("GET", pattern)
+		}
+		if i%50 == 0 {
+			r.Group(fmt.Sprintf("/group%d", i))
+		}
+	}
+	close(stop)
+	wg.Wait()
+}
+
+func TestRoute_ConcurrentMeta(t *testing.T) {
+	r := New()
+	handler := func(c *Context) {
+		role, _ := c.Route().Get("role")
+		c.String(http.StatusOK, "%v", role)
+	}
+
+	var wg sync.WaitGroup
+	stop := make(chan struct{})
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+				}
+				serve(r, "GET", "/plugin/3")
+				for _, route := range r.Routes() {
+					route.Get("role")
+				}
+			}
+		}()
+	}
+
+	for i := 0; i < 200; i++ {
+		pattern := fmt.Sprintf("/plugin/%d", i%10)
+		r.RemoveRoute("GET", pattern)
+		route := r.GET(pattern, handler).Summary("plugin").Tags("plugins").Response(http.StatusOK, "")
+		for j := 0; j <= i; j++ {
+			route.Set("role", j)
+		}
+	}
+	close(stop)
+	wg.Wait()
+
+	if w := serve(r, "GET", "/plugin/3"); w.Body.String() != "193" {
+		t.Fatalf("expect the metadata of the last registration, but got %q", w.Body.String())
+	}
+}
+
+func TestRouterGroup_HandlerChain(t *testing.T) {
+	r := New()
+	var order []string
+	trace := func(name string) HandlerFunc {
+		return func(c *Context) {
+			order = append(order, name)
+			c.Next()
+		}
+	}
+	auth := func(c *Context) {
+		role, _ := c.Route().Get("role")
+		if c.Query("role") != role {
+			c.Fail(http.StatusForbidden, "forbidden")
+			return
+		}
+		order = append(order, "auth "+c.FullPath())
+	}
+	api := r.Group("/api")
+	api.Use(trace("group"))
+	api.DELETE("/users/:id", auth, trace("audit"), func(c *Context) {
+		order = append(order, "handler")
+		c.Status(http.StatusNoContent)
+	}).Set("role", "admin")
+
+	if w := serve(r, "DELETE", "/api/users/1?role=admin"); w.Code != http.StatusNoContent {
+		t.Fatalf("expect 204, but got %d", w.Code)
+	}
+	if fmt.Sprint(order) != "[group auth /api/users/:id audit handler]" {
+		t.Fatalf("unexpected order %v", order)
+	}
+
+	order = nil
+	if w := serve(r, "DELETE", "/api/users/1"); w.Code != http.StatusForbidden {
+		t.Fatalf("expect 403, but got %d", w.Code)
+	}
+	if fmt.Sprint(order) != "[group]" {
+		t.Fatalf("expect the chain to stop at auth, but got %v", order)
+	}
+}
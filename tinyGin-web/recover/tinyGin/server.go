@@ -0,0 +1,45 @@
+package tinyGin
+
+import (
+	"net/http"
+	"time"
+)
+
+// ServerConfig holds the limits of the http.Server, zero means no limit except for
+// MaxHeaderBytes which then falls back to http.DefaultMaxHeaderBytes
+type ServerConfig struct {
+	// ReadHeaderTimeout bounds reading the request headers, it stops slowloris clients
+	ReadHeaderTimeout time.Duration
+	// ReadTimeout bounds reading the whole request, including the body
+	ReadTimeout time.Duration
+	// WriteTimeout bounds writing the response, keep it 0 for SSE and long downloads
+	WriteTimeout time.Duration
+	// IdleTimeout bounds waiting for the next request of a keep-alive connection
+	IdleTimeout time.Duration
+	// MaxHeaderBytes limits the size of the request line and headers
+	MaxHeaderBytes int
+}
+
+// DefaultServerConfig is the config of the engines created by New
+func DefaultServerConfig() ServerConfig {
+	return ServerConfig{
+		ReadHeaderTimeout: 10 * time.Second,
+		ReadTimeout:       60 * time.Second,
+		IdleTimeout:       120 * time.Second,
+		MaxHeaderBytes:    http.DefaultMaxHeaderBytes,
+	}
+}
+
+// NewServer returns a http.Server of the engine with engine.Server applied,
+// e.g. for ListenAndServeTLS
+func (engine *Engine) NewServer(addr string) *http.Server {
+	return &http.Server{
+		Addr:              addr,
+		Handler:           engine,
+		ReadHeaderTimeout: engine.Server.ReadHeaderTimeout,
+		ReadTimeout:       engine.Server.ReadTimeout,
+		WriteTimeout:      engine.Server.WriteTimeout,
+		IdleTimeout:       engine.Server.IdleTimeout,
+		MaxHeaderBytes:    engine.Server.MaxHeaderBytes,
+	}
+}
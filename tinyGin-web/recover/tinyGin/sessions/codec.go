@@ -0,0 +1,126 @@
+package sessions
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/gob"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	ErrInvalidValue     = errors.New("sessions: invalid cookie value")
+	ErrInvalidSignature = errors.New("sessions: invalid signature")
+	ErrExpired          = errors.New("sessions: cookie expired")
+	ErrDecryption       = errors.New("sessions: failed to decrypt value")
+	ErrCookieTooLarge   = errors.New("sessions: cookie exceeds 4096 bytes")
+)
+
+func init() {
+	// flashes are stored as []interface{} inside Values
+	gob.Register([]interface{}{})
+}
+
+// codec signs values with HMAC-SHA256 and optionally encrypts them with AES-GCM
+type codec struct {
+	hashKey []byte
+	aead    cipher.AEAD // nil means no encryption
+}
+
+// newCodec is the constructor of codec, encryptKey must be empty or 16, 24, 32 bytes long
+func newCodec(hashKey, encryptKey []byte) (*codec, error) {
+	if len(hashKey) == 0 {
+		return nil, errors.New("sessions: hash key is required")
+	}
+	c := &codec{hashKey: hashKey}
+	if len(encryptKey) > 0 {
+		block, err := aes.NewCipher(encryptKey)
+		if err != nil {
+			return nil, err
+		}
+		if c.aead, err = cipher.NewGCM(block); err != nil {
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+// encode serializes value into "timestamp|payload|mac", base64 encoded.
+// name is part of the mac so a value can't be replayed under another cookie
+func (c *codec) encode(name string, value interface{}) (string, error) {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(value); err != nil {
+		return "", err
+	}
+	data := buf.Bytes()
+	if c.aead != nil {
+		nonce := make([]byte, c.aead.NonceSize())
+		if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+			return "", err
+		}
+		data = c.aead.Seal(nonce, nonce, data, []byte(name))
+	}
+	payload := strconv.FormatInt(time.Now().Unix(), 10) + "|" + base64.RawURLEncoding.EncodeToString(data)
+	mac := c.mac(name, payload)
+	return base64.RawURLEncoding.EncodeToString([]byte(payload + "|" + mac)), nil
+}
+
+// decode verifies and deserializes value into dst, maxAge <= 0 means never expire
+func (c *codec) decode(name string, value string, dst interface{}, maxAge int) error {
+	raw, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return ErrInvalidValue
+	}
+	parts := strings.Split(string(raw), "|")
+	if len(parts) != 3 {
+		return ErrInvalidValue
+	}
+	payload := parts[0] + "|" + parts[1]
+	if !hmac.Equal([]byte(c.mac(name, payload)), []byte(parts[2])) {
+		return ErrInvalidSignature
+	}
+	ts, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return ErrInvalidValue
+	}
+	if maxAge > 0 && time.Now().Unix()-ts > int64(maxAge) {
+		return ErrExpired
+	}
+	data, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return ErrInvalidValue
+	}
+	if c.aead != nil {
+		size := c.aead.NonceSize()
+		if len(data) < size {
+			return ErrDecryption
+		}
+		if data, err = c.aead.Open(nil, data[:size], data[size:], []byte(name)); err != nil {
+			return ErrDecryption
+		}
+	}
+	return gob.NewDecoder(bytes.NewReader(data)).Decode(dst)
+}
+
+func (c *codec) mac(name string, payload string) string {
+	h := hmac.New(sha256.New, c.hashKey)
+	h.Write([]byte(name + "|" + payload))
+	return base64.RawURLEncoding.EncodeToString(h.Sum(nil))
+}
+
+// newID returns a random session id
+func newID() (string, error) {
+	b := make([]byte, 32)
+	if _, err := io.ReadFull(rand.Reader, b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
@@ -0,0 +1,57 @@
+package sessions
+
+import (
+	"net/http"
+)
+
+// maxCookieSize is the size of the name and value of a cookie browsers keep at least
+const maxCookieSize = 4096
+
+// CookieStore keeps all session values in a signed, optionally encrypted cookie
+type CookieStore struct {
+	Options *Options
+	codec   *codec
+}
+
+// NewCookieStore is the constructor of CookieStore.
+// hashKey signs the cookie, a 16, 24 or 32 bytes encryptKey enables AES-GCM encryption
+func NewCookieStore(hashKey []byte, encryptKey []byte) (*CookieStore, error) {
+	c, err := newCodec(hashKey, encryptKey)
+	if err != nil {
+		return nil, err
+	}
+	return &CookieStore{Options: DefaultOptions(), codec: c}, nil
+}
+
+func (store *CookieStore) Load(req *http.Request, name string) (*Session, error) {
+	s := NewSession(store, name, store.Options)
+	cookie, err := req.Cookie(name)
+	if err != nil {
+		return s, nil
+	}
+	values := make(map[string]interface{})
+	if err := store.codec.decode(name, cookie.Value, &values, s.Options.MaxAge); err != nil {
+		return s, err
+	}
+	s.Values = values
+	s.IsNew = false
+	return s, nil
+}
+
+// Save writes the session cookie, ErrCookieTooLarge is returned instead of a cookie
+// browsers would silently drop
+func (store *CookieStore) Save(w http.ResponseWriter, s *Session) error {
+	if s.Options.MaxAge < 0 {
+		http.SetCookie(w, s.Options.cookie(s.name, ""))
+		return nil
+	}
+	value, err := store.codec.encode(s.name, s.Values)
+	if err != nil {
+		return err
+	}
+	if len(s.name)+1+len(value) > maxCookieSize {
+		return ErrCookieTooLarge
+	}
+	http.SetCookie(w, s.Options.cookie(s.name, value))
+	return nil
+}
@@ -0,0 +1,105 @@
+package sessions
+
+import (
+	"bytes"
+	"encoding/gob"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+type fileEntry struct {
+	Values  map[string]interface{}
+	Expires time.Time
+}
+
+// FileStore keeps every session in a gob encoded file of dir,
+// the cookie only holds the session id
+type FileStore struct {
+	Options *Options
+	idCodec
+
+	mu  sync.Mutex
+	dir string
+}
+
+// NewFileStore is the constructor of FileStore, dir is created if it does not exist
+func NewFileStore(dir string, hashKey []byte) (*FileStore, error) {
+	c, err := newCodec(hashKey, nil)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, err
+	}
+	return &FileStore{Options: DefaultOptions(), idCodec: idCodec{codec: c}, dir: dir}, nil
+}
+
+func (store *FileStore) filename(id string) string {
+	// ids are base64url, they never contain a path separator
+	return filepath.Join(store.dir, "session_"+id)
+}
+
+func (store *FileStore) Load(req *http.Request, name string) (*Session, error) {
+	s := NewSession(store, name, store.Options)
+	id, err := store.load(req, name, s.Options.MaxAge)
+	if id == "" {
+		return s, err
+	}
+
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	data, err := ioutil.ReadFile(store.filename(id))
+	if os.IsNotExist(err) {
+		return s, nil
+	}
+	if err != nil {
+		return s, err
+	}
+	var entry fileEntry
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&entry); err != nil {
+		return s, err
+	}
+	if !entry.Expires.IsZero() && time.Now().After(entry.Expires) {
+		return s, os.Remove(store.filename(id))
+	}
+	for k, v := range entry.Values {
+		s.Values[k] = v
+	}
+	s.ID = id
+	s.IsNew = false
+	return s, nil
+}
+
+func (store *FileStore) Save(w http.ResponseWriter, s *Session) error {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	if s.Options.MaxAge < 0 {
+		if s.ID != "" {
+			if err := os.Remove(store.filename(s.ID)); err != nil && !os.IsNotExist(err) {
+				return err
+			}
+		}
+		return store.save(w, s)
+	}
+	if s.ID == "" {
+		id, err := newID()
+		if err != nil {
+			return err
+		}
+		s.ID = id
+	}
+	var buf bytes.Buffer
+	entry := fileEntry{Values: s.Values, Expires: expiresAt(s.Options.MaxAge)}
+	if err := gob.NewEncoder(&buf).Encode(&entry); err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(store.filename(s.ID), buf.Bytes(), 0600); err != nil {
+		return err
+	}
+	return store.save(w, s)
+}
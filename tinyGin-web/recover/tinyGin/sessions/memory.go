@@ -0,0 +1,144 @@
+package sessions
+
+import (
+	"net/http"
+	"sync"
+	"time"
+)
+
+// idCodec stores only the signed session id in the cookie,
+// it is shared by the server-side stores
+type idCodec struct {
+	codec *codec
+}
+
+func (ic idCodec) load(req *http.Request, name string, maxAge int) (string, error) {
+	cookie, err := req.Cookie(name)
+	if err != nil {
+		return "", nil
+	}
+	var id string
+	if err := ic.codec.decode(name, cookie.Value, &id, maxAge); err != nil {
+		return "", err
+	}
+	return id, nil
+}
+
+func (ic idCodec) save(w http.ResponseWriter, s *Session) error {
+	if s.Options.MaxAge < 0 {
+		http.SetCookie(w, s.Options.cookie(s.name, ""))
+		return nil
+	}
+	value, err := ic.codec.encode(s.name, s.ID)
+	if err != nil {
+		return err
+	}
+	http.SetCookie(w, s.Options.cookie(s.name, value))
+	return nil
+}
+
+// expiresAt returns the zero time when the session never expires
+func expiresAt(maxAge int) time.Time {
+	if maxAge <= 0 {
+		return time.Time{}
+	}
+	return time.Now().Add(time.Duration(maxAge) * time.Second)
+}
+
+type memoryEntry struct {
+	values  map[string]interface{}
+	expires time.Time
+}
+
+func (e *memoryEntry) expired(now time.Time) bool {
+	return !e.expires.IsZero() && now.After(e.expires)
+}
+
+// MemoryStore keeps session values in memory, the cookie only holds the session id
+type MemoryStore struct {
+	Options *Options
+	idCodec
+
+	mu      sync.Mutex
+	entries map[string]*memoryEntry
+}
+
+// NewMemoryStore is the constructor of MemoryStore, hashKey signs the session id
+func NewMemoryStore(hashKey []byte) (*MemoryStore, error) {
+	c, err := newCodec(hashKey, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &MemoryStore{
+		Options: DefaultOptions(),
+		idCodec: idCodec{codec: c},
+		entries: make(map[string]*memoryEntry),
+	}, nil
+}
+
+func (store *MemoryStore) Load(req *http.Request, name string) (*Session, error) {
+	s := NewSession(store, name, store.Options)
+	id, err := store.load(req, name, s.Options.MaxAge)
+	if id == "" {
+		return s, err
+	}
+
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	entry, ok := store.entries[id]
+	if !ok {
+		return s, nil
+	}
+	if entry.expired(time.Now()) {
+		delete(store.entries, id)
+		return s, nil
+	}
+	for k, v := range entry.values {
+		s.Values[k] = v
+	}
+	s.ID = id
+	s.IsNew = false
+	return s, nil
+}
+
+func (store *MemoryStore) Save(w http.ResponseWriter, s *Session) error {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	store.gc()
+
+	if s.Options.MaxAge < 0 {
+		delete(store.entries, s.ID)
+		return store.save(w, s)
+	}
+	if s.ID == "" {
+		id, err := newID()
+		if err != nil {
+			return err
+		}
+		s.ID = id
+	}
+	values := make(map[string]interface{}, len(s.Values))
+	for k, v := range s.Values {
+		values[k] = v
+	}
+	store.entries[s.ID] = &memoryEntry{values: values, expires: expiresAt(s.Options.MaxAge)}
+	return store.save(w, s)
+}
+
+// Len returns the number of sessions which are not expired
+func (store *MemoryStore) Len() int {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	store.gc()
+	return len(store.entries)
+}
+
+// gc removes expired entries, the caller must hold store.mu
+func (store *MemoryStore) gc() {
+	now := time.Now()
+	for id, entry := range store.entries {
+		if entry.expired(now) {
+			delete(store.entries, id)
+		}
+	}
+}
@@ -0,0 +1,139 @@
+package sessions
+
+import (
+	"log"
+	"net/http"
+
+	"tinyGin"
+)
+
+const (
+	sessionKey = "tinyGin/sessions"
+	flashKey   = "_flash"
+)
+
+// Options stores the cookie attributes of a session
+type Options struct {
+	Path     string
+	Domain   string
+	MaxAge   int // seconds, <0 deletes the session, 0 means a browser session
+	Secure   bool
+	HttpOnly bool
+	SameSite http.SameSite
+}
+
+// DefaultOptions is used by stores created without options
+func DefaultOptions() *Options {
+	return &Options{
+		Path:     "/",
+		MaxAge:   86400 * 30,
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	}
+}
+
+func (o *Options) cookie(name string, value string) *http.Cookie {
+	return &http.Cookie{
+		Name:     name,
+		Value:    value,
+		Path:     o.Path,
+		Domain:   o.Domain,
+		MaxAge:   o.MaxAge,
+		Secure:   o.Secure,
+		HttpOnly: o.HttpOnly,
+		SameSite: o.SameSite,
+	}
+}
+
+// Store loads and saves sessions
+type Store interface {
+	// Load returns the session named name of req,
+	// a new session is returned together with the error if the stored one is invalid
+	Load(req *http.Request, name string) (*Session, error)
+	// Save writes the session to the store and the cookie to w
+	Save(w http.ResponseWriter, s *Session) error
+}
+
+// Session holds the values of one client
+type Session struct {
+	ID      string // empty for the cookie store
+	Values  map[string]interface{}
+	Options *Options
+	IsNew   bool
+
+	name  string
+	store Store
+	w     http.ResponseWriter
+}
+
+// NewSession is called by stores to create an empty session
+func NewSession(store Store, name string, options *Options) *Session {
+	opts := *options
+	return &Session{
+		Values:  make(map[string]interface{}),
+		Options: &opts,
+		IsNew:   true,
+		name:    name,
+		store:   store,
+	}
+}
+
+// Name returns the cookie name of the session
+func (s *Session) Name() string {
+	return s.name
+}
+
+func (s *Session) Get(key string) interface{} {
+	return s.Values[key]
+}
+
+func (s *Session) Set(key string, value interface{}) {
+	s.Values[key] = value
+}
+
+func (s *Session) Delete(key string) {
+	delete(s.Values, key)
+}
+
+// Clear deletes all values of the session
+func (s *Session) Clear() {
+	for key := range s.Values {
+		delete(s.Values, key)
+	}
+}
+
+// AddFlash adds a value which is removed once it is read by Flashes
+func (s *Session) AddFlash(value interface{}) {
+	flashes, _ := s.Values[flashKey].([]interface{})
+	s.Values[flashKey] = append(flashes, value)
+}
+
+// Flashes returns and removes the flash values, remember to Save the session after reading
+func (s *Session) Flashes() []interface{} {
+	flashes, _ := s.Values[flashKey].([]interface{})
+	delete(s.Values, flashKey)
+	return flashes
+}
+
+// Save writes the session, it must be called before the response body is written
+func (s *Session) Save() error {
+	return s.store.Save(s.w, s)
+}
+
+// Sessions is the middleware to make the session named name available by Default
+func Sessions(name string, store Store) tinyGin.HandlerFunc {
+	return func(c *tinyGin.Context) {
+		s, err := store.Load(c.Req, name)
+		if err != nil {
+			log.Printf("sessions: %s: %v", name, err)
+		}
+		s.w = c.Writer
+		c.Set(sessionKey, s)
+		c.Next()
+	}
+}
+
+// Default returns the session loaded by the Sessions middleware
+func Default(c *tinyGin.Context) *Session {
+	return c.MustGet(sessionKey).(*Session)
+}
@@ -0,0 +1,107 @@
+package sessions
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"tinyGin"
+)
+
+var hashKey = []byte("0123456789abcdef0123456789abcdef")
+
+func newTestEngine(store Store) *tinyGin.Engine {
+	r := tinyGin.New()
+	r.Use(Sessions("tiny", store))
+	r.GET("/set", func(c *tinyGin.Context) {
+		s := Default(c)
+		s.Set("name", c.Query("name"))
+		s.AddFlash("saved")
+		_ = s.Save()
+		c.String(http.StatusOK, "ok")
+	})
+	r.GET("/get", func(c *tinyGin.Context) {
+		s := Default(c)
+		flashes := s.Flashes()
+		_ = s.Save()
+		name, _ := s.Get("name").(string)
+		c.String(http.StatusOK, "%s %d", name, len(flashes))
+	})
+	return r
+}
+
+// roundTrip sets a session value and reads it back twice with the returned cookie
+func roundTrip(t *testing.T, store Store) {
+	t.Helper()
+	r := newTestEngine(store)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/set?name=tom", nil))
+	cookies := w.Result().Cookies()
+	if len(cookies) != 1 {
+		t.Fatalf("expect 1 cookie, but got %d", len(cookies))
+	}
+
+	for _, expect := range []string{"tom 1", "tom 0"} {
+		req := httptest.NewRequest("GET", "/get", nil)
+		req.AddCookie(cookies[0])
+		w = httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		if w.Body.String() != expect {
+			t.Fatalf("expect %q, but got %q", expect, w.Body.String())
+		}
+		cookies = w.Result().Cookies()
+	}
+}
+
+func TestCookieStore(t *testing.T) {
+	store, _ := NewCookieStore(hashKey, nil)
+	roundTrip(t, store)
+}
+
+func TestCookieStore_Encrypted(t *testing.T) {
+	store, err := NewCookieStore(hashKey, []byte("fedcba9876543210"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	roundTrip(t, store)
+}
+
+func TestCookieStore_Tampered(t *testing.T) {
+	store, _ := NewCookieStore(hashKey, nil)
+	r := newTestEngine(store)
+	req := httptest.NewRequest("GET", "/get", nil)
+	req.AddCookie(&http.Cookie{Name: "tiny", Value: "dGFtcGVyZWQ"})
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Body.String() != " 0" {
+		t.Fatalf("expect a new session, but got %q", w.Body.String())
+	}
+}
+
+func TestCookieStore_TooLarge(t *testing.T) {
+	store, _ := NewCookieStore(hashKey, nil)
+	s := NewSession(store, "tiny", store.Options)
+	s.Values["blob"] = strings.Repeat("x", 4096)
+	w := httptest.NewRecorder()
+	if err := store.Save(w, s); err != ErrCookieTooLarge || len(w.Result().Cookies()) != 0 {
+		t.Fatalf("expect ErrCookieTooLarge and no cookie, but got %v", err)
+	}
+}
+
+func TestMemoryStore(t *testing.T) {
+	store, _ := NewMemoryStore(hashKey)
+	roundTrip(t, store)
+	if store.Len() != 1 {
+		t.Fatalf("expect 1 session, but got %d", store.Len())
+	}
+}
+
+func TestFileStore(t *testing.T) {
+	store, err := NewFileStore(t.TempDir(), hashKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	roundTrip(t, store)
+}
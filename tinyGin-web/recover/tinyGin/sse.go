@@ -0,0 +1,112 @@
+package tinyGin
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// ServerSentEvent is one message of the text/event-stream format
+// refer https://html.spec.whatwg.org/multipage/server-sent-events.html
+type ServerSentEvent struct {
+	Event string      // event name, empty means "message"
+	ID    string      // last event id
+	Retry uint        // reconnection time in milliseconds, 0 means unset
+	Data  interface{} // strings and []byte are written as is, others are encoded as JSON
+}
+
+// newlines can't appear in a field, they would start a new one
+var fieldReplacer = strings.NewReplacer("\r\n", "", "\n", "", "\r", "")
+
+// Encode writes the event in the SSE wire format
+func (e ServerSentEvent) Encode(w io.Writer) error {
+	var b strings.Builder
+	if e.ID != "" {
+		b.WriteString("id: " + fieldReplacer.Replace(e.ID) + "\n")
+	}
+	if e.Event != "" {
+		b.WriteString("event: " + fieldReplacer.Replace(e.Event) + "\n")
+	}
+	if e.Retry > 0 {
+		b.WriteString(fmt.Sprintf("retry: %d\n", e.Retry))
+	}
+
+	var data string
+	switch value := e.Data.(type) {
+	case string:
+		data = value
+	case []byte:
+		data = string(value)
+	default:
+		encoded, err := json.Marshal(value)
+		if err != nil {
+			return err
+		}
+		data = string(encoded)
+	}
+	data = strings.ReplaceAll(strings.ReplaceAll(data, "\r\n", "\n"), "\r", "\n")
+	for _, line := range strings.Split(data, "\n") {
+		b.WriteString("data: " + line + "\n")
+	}
+	b.WriteString("\n")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// SSE is the middleware for event stream routes, it sets the headers
+// and disables the buffering of proxies
+func SSE() HandlerFunc {
+	return func(c *Context) {
+		c.SetHeader("Content-Type", "text/event-stream")
+		c.SetHeader("Cache-Control", "no-cache")
+		c.SetHeader("Connection", "keep-alive")
+		// nginx buffers responses unless told otherwise
+		c.SetHeader("X-Accel-Buffering", "no")
+		c.Next()
+	}
+}
+
+// Flush sends the buffered data to the client
+func (c *Context) Flush() {
+	if f, ok := c.Writer.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Stream calls step and flushes until step returns false or the client disconnects,
+// it returns true if the client disconnected in the middle of the stream
+func (c *Context) Stream(step func(w io.Writer) bool) bool {
+	done := c.Req.Context().Done()
+	for {
+		select {
+		case <-done:
+			return true
+		default:
+			keepOpen := step(c.Writer)
+			c.Flush()
+			if !keepOpen {
+				return false
+			}
+		}
+	}
+}
+
+// SSEvent writes a named event and flushes it
+func (c *Context) SSEvent(name string, data interface{}) {
+	c.WriteSSE(ServerSentEvent{Event: name, Data: data})
+}
+
+// WriteSSE writes a event with all its fields and flushes it
+func (c *Context) WriteSSE(event ServerSentEvent) error {
+	if c.Writer.Header().Get("Content-Type") == "" {
+		c.SetHeader("Content-Type", "text/event-stream")
+	}
+	if err := event.Encode(c.Writer); err != nil {
+		return err
+	}
+	c.Flush()
+	return nil
+}
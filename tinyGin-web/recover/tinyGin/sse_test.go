@@ -0,0 +1,65 @@
+package tinyGin
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestServerSentEvent_Encode(t *testing.T) {
+	var b strings.Builder
+	event := ServerSentEvent{Event: "tick", ID: "7\n", Retry: 3000, Data: "line1\nline2"}
+	if err := event.Encode(&b); err != nil {
+		t.Fatal(err)
+	}
+	expect := "id: 7\nevent: tick\nretry: 3000\ndata: line1\ndata: line2\n\n"
+	if b.String() != expect {
+		t.Fatalf("expect %q, but got %q", expect, b.String())
+	}
+
+	b.Reset()
+	_ = ServerSentEvent{Data: H{"n": 1}}.Encode(&b)
+	if b.String() != "data: {\"n\":1}\n\n" {
+		t.Fatalf("unexpected json event %q", b.String())
+	}
+}
+
+func TestContext_Stream(t *testing.T) {
+	r := New()
+	stream := r.Group("/stream")
+	stream.Use(SSE())
+	stream.GET("/events", func(c *Context) {
+		n := 0
+		c.Stream(func(w io.Writer) bool {
+			c.SSEvent("count", fmt.Sprint(n))
+			n++
+			return n < 3
+		})
+	})
+
+	ts := httptest.NewServer(r)
+	defer ts.Close()
+	res, err := http.Get(ts.URL + "/stream/events")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+	if res.Header.Get("Content-Type") != "text/event-stream" {
+		t.Fatalf("unexpected content type %s", res.Header.Get("Content-Type"))
+	}
+
+	var data []string
+	scanner := bufio.NewScanner(res.Body)
+	for scanner.Scan() {
+		if strings.HasPrefix(scanner.Text(), "data: ") {
+			data = append(data, strings.TrimPrefix(scanner.Text(), "data: "))
+		}
+	}
+	if strings.Join(data, ",") != "0,1,2" {
+		t.Fatalf("unexpected events %v", data)
+	}
+}
@@ -0,0 +1,247 @@
+package tinyGin
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// AutoReload tells whether the templates are parsed again when their files change
+type AutoReload int
+
+const (
+	// AutoReloadDebug reloads the templates while tinyGin runs in DebugMode, the default
+	AutoReloadDebug AutoReload = iota
+	// AutoReloadOn always reloads the templates
+	AutoReloadOn
+	// AutoReloadOff never reloads the templates
+	AutoReloadOff
+)
+
+// enabled evaluates AutoReloadDebug by the current mode
+func (reload AutoReload) enabled() bool {
+	if reload == AutoReloadDebug {
+		return IsDebugging()
+	}
+	return reload == AutoReloadOn
+}
+
+// htmlReloadInterval is the least time between two checks of the template files
+var htmlReloadInterval = time.Second
+
+// htmlTemplate is a parsed template together with where it comes from,
+// so it can be parsed again when the files change
+type htmlTemplate struct {
+	fsys     fs.FS    // nil means the OS file system
+	patterns []string // glob patterns or plain file names
+	isSet    bool     // a named set executes its first file, the layout
+
+	tmpl    *template.Template
+	files   []string
+	modTime time.Time
+	checked int64 // unix nano of the last check for changes, accessed atomically
+}
+
+func (t *htmlTemplate) glob() ([]string, error) {
+	var files []string
+	for _, pattern := range t.patterns {
+		var matches []string
+		var err error
+		if t.fsys == nil {
+			matches, err = filepath.Glob(pattern)
+		} else {
+			matches, err = fs.Glob(t.fsys, pattern)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if len(matches) == 0 {
+			return nil, fmt.Errorf("tinyGin: pattern matches no files: %s", pattern)
+		}
+		files = append(files, matches...)
+	}
+	return files, nil
+}
+
+// latest returns the latest modification time of files
+func (t *htmlTemplate) latest(files []string) (time.Time, error) {
+	var latest time.Time
+	for _, file := range files {
+		var info fs.FileInfo
+		var err error
+		if t.fsys == nil {
+			info, err = os.Stat(file)
+		} else {
+			info, err = fs.Stat(t.fsys, file)
+		}
+		if err != nil {
+			return latest, err
+		}
+		if info.ModTime().After(latest) {
+			latest = info.ModTime()
+		}
+	}
+	return latest, nil
+}
+
+func (t *htmlTemplate) parse(funcMap template.FuncMap) error {
+	files, err := t.glob()
+	if err != nil {
+		return err
+	}
+	modTime, err := t.latest(files)
+	if err != nil {
+		return err
+	}
+
+	name := ""
+	if t.isSet {
+		name = filepath.Base(files[0])
+	}
+	tmpl := template.New(name).Funcs(funcMap)
+	if t.fsys == nil {
+		tmpl, err = tmpl.ParseFiles(files...)
+	} else {
+		tmpl, err = tmpl.ParseFS(t.fsys, files...)
+	}
+	if err != nil {
+		return err
+	}
+	t.tmpl, t.files, t.modTime = tmpl, files, modTime
+	return nil
+}
+
+// due reports whether the files should be checked for changes, only one of the
+// concurrent callers within htmlReloadInterval gets true
+func (t *htmlTemplate) due() bool {
+	now := time.Now().UnixNano()
+	last := atomic.LoadInt64(&t.checked)
+	if now-last < int64(htmlReloadInterval) {
+		return false
+	}
+	return atomic.CompareAndSwapInt64(&t.checked, last, now)
+}
+
+// changed reports whether files were added, removed or modified since the last parse
+func (t *htmlTemplate) changed() bool {
+	files, err := t.glob()
+	if err != nil || len(files) != len(t.files) {
+		return true
+	}
+	for i := range files {
+		if files[i] != t.files[i] {
+			return true
+		}
+	}
+	modTime, err := t.latest(files)
+	return err != nil || modTime.After(t.modTime)
+}
+
+func (t *htmlTemplate) execute(w io.Writer, name string, data interface{}) error {
+	if t.isSet {
+		return t.tmpl.Execute(w, data)
+	}
+	return t.tmpl.ExecuteTemplate(w, name, data)
+}
+
+// htmlRender holds the global template and the named sets of an Engine
+type htmlRender struct {
+	mu     sync.RWMutex
+	global *htmlTemplate
+	sets   map[string]*htmlTemplate
+}
+
+// SetFuncMap for custom render function, it must be called before loading templates
+func (engine *Engine) SetFuncMap(funcMap template.FuncMap) {
+	engine.funcMap = funcMap
+}
+
+func (engine *Engine) loadHTML(name string, t *htmlTemplate) {
+	if err := t.parse(engine.funcMap); err != nil {
+		panic(err)
+	}
+	engine.html.mu.Lock()
+	defer engine.html.mu.Unlock()
+	if !t.isSet {
+		engine.html.global = t
+		return
+	}
+	if engine.html.sets == nil {
+		engine.html.sets = make(map[string]*htmlTemplate)
+	}
+	engine.html.sets[name] = t
+}
+
+// LoadHTMLGlob loads all templates matched by pattern into the global set
+func (engine *Engine) LoadHTMLGlob(pattern string) {
+	engine.loadHTML("", &htmlTemplate{patterns: []string{pattern}})
+}
+
+// LoadHTMLFiles loads the templates of files into the global set
+func (engine *Engine) LoadHTMLFiles(files ...string) {
+	engine.loadHTML("", &htmlTemplate{patterns: files})
+}
+
+// LoadHTMLFS loads the templates of fsys matched by patterns into the global set,
+// fsys is usually an embed.FS
+func (engine *Engine) LoadHTMLFS(fsys fs.FS, patterns ...string) {
+	engine.loadHTML("", &htmlTemplate{fsys: fsys, patterns: patterns})
+}
+
+// AddHTMLSet adds a template set rendered by c.HTML(code, name, data).
+// The first file is the layout which is executed, the following files are the page
+// and its partials, e.g. the layout calls {{template "content" .}} defined by the page
+func (engine *Engine) AddHTMLSet(name string, files ...string) {
+	engine.loadHTML(name, &htmlTemplate{patterns: files, isSet: true})
+}
+
+// AddHTMLSetFS is the same as AddHTMLSet but reads the files from fsys
+func (engine *Engine) AddHTMLSetFS(name string, fsys fs.FS, files ...string) {
+	engine.loadHTML(name, &htmlTemplate{fsys: fsys, patterns: files, isSet: true})
+}
+
+// lookupHTML returns the set named name, or the global set which holds the template name.
+// When HTMLAutoReload is enabled the files are checked at most once per htmlReloadInterval under the
+// read lock, the write lock is only taken to parse changed files again
+func (engine *Engine) lookupHTML(name string) (*htmlTemplate, error) {
+	engine.html.mu.RLock()
+	t, ok := engine.html.sets[name]
+	if !ok {
+		t = engine.html.global
+	}
+	changed := t != nil && engine.HTMLAutoReload.enabled() && t.due() && t.changed()
+	engine.html.mu.RUnlock()
+	if t == nil {
+		return nil, fmt.Errorf("tinyGin: html template %q is not loaded", name)
+	}
+	if !changed {
+		return t, nil
+	}
+
+	engine.html.mu.Lock()
+	defer engine.html.mu.Unlock()
+	if t.changed() {
+		reloaded := *t
+		if err := reloaded.parse(engine.funcMap); err != nil {
+			return nil, err
+		}
+		*t = reloaded
+	}
+	return t, nil
+}
+
+func (engine *Engine) renderHTML(w io.Writer, name string, data interface{}) error {
+	t, err := engine.lookupHTML(name)
+	if err != nil {
+		return err
+	}
+	engine.html.mu.RLock()
+	defer engine.html.mu.RUnlock()
+	return t.execute(w, name, data)
+}
@@ -0,0 +1,95 @@
+package tinyGin
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+func render(r *Engine, name string) string {
+	r.GET("/"+name, func(c *Context) {
+		c.HTML(http.StatusOK, name, H{"title": "tiny"})
+	})
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/"+name, nil))
+	return w.Body.String()
+}
+
+func TestEngine_AddHTMLSet(t *testing.T) {
+	fsys := fstest.MapFS{
+		"layouts/base.tmpl":  {Data: []byte(`<main>{{template "content" .}}</main>`)},
+		"layouts/admin.tmpl": {Data: []byte(`<admin>{{template "content" .}}</admin>`)},
+		"pages/index.tmpl":   {Data: []byte(`{{define "content"}}index {{.title}}{{end}}`)},
+		"pages/users.tmpl":   {Data: []byte(`{{define "content"}}users{{end}}`)},
+	}
+	r := New()
+	r.AddHTMLSetFS("index", fsys, "layouts/base.tmpl", "pages/index.tmpl")
+	r.AddHTMLSetFS("users", fsys, "layouts/admin.tmpl", "pages/users.tmpl")
+
+	if body := render(r, "index"); body != "<main>index tiny</main>" {
+		t.Fatalf("unexpected index %q", body)
+	}
+	if body := render(r, "users"); body != "<admin>users</admin>" {
+		t.Fatalf("unexpected users %q", body)
+	}
+}
+
+func TestEngine_LoadHTMLFS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"templates/hello.tmpl": {Data: []byte(`hello {{.title}}`)},
+	}
+	r := New()
+	r.LoadHTMLFS(fsys, "templates/*.tmpl")
+	if body := render(r, "hello.tmpl"); body != "hello tiny" {
+		t.Fatalf("unexpected body %q", body)
+	}
+	if body := render(r, "missing.tmpl"); body == "" {
+		t.Fatal("expect an error for a missing template")
+	}
+}
+
+func TestEngine_HTMLAutoReload(t *testing.T) {
+	interval := htmlReloadInterval
+	htmlReloadInterval = 0
+	defer func() { htmlReloadInterval = interval }()
+
+	tests := []struct {
+		mode   string
+		reload AutoReload
+		expect string
+	}{
+		{DebugMode, AutoReloadDebug, "v2"},
+		{ReleaseMode, AutoReloadDebug, "v1"},
+		{DebugMode, AutoReloadOff, "v1"},
+		{ReleaseMode, AutoReloadOn, "v2"},
+	}
+	for _, tt := range tests {
+		file := filepath.Join(t.TempDir(), "page.tmpl")
+		_ = ioutil.WriteFile(file, []byte("v1"), 0644)
+
+		var body string
+		withMode(tt.mode, func() {
+			r := New()
+			r.HTMLAutoReload = tt.reload
+			r.LoadHTMLFiles(file)
+			if body := render(r, "page.tmpl"); body != "v1" {
+				t.Fatalf("unexpected body %q", body)
+			}
+
+			_ = ioutil.WriteFile(file, []byte("v2"), 0644)
+			later := time.Now().Add(time.Second)
+			_ = os.Chtimes(file, later, later)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest("GET", "/page.tmpl", nil))
+			body = w.Body.String()
+		})
+		if body != tt.expect {
+			t.Fatalf("expect %q with reload %d in %s mode, but got %q", tt.expect, tt.reload, tt.mode, body)
+		}
+	}
+}
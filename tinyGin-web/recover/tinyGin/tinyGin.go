@@ -1,11 +1,16 @@
 package tinyGin
 
 import (
+	"context"
 	"html/template"
-	"log"
 	"net/http"
 	"path"
 	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"tinyGin/ws"
 )
 
 type HandlerFunc func(*Context)
@@ -17,20 +22,54 @@ type (
 		middlewares []HandlerFunc // support middleware
 		parent      *RouterGroup  // support nesting
 		engine      *Engine       // all groups share a Engine instance
+		host        *hostRouter   // nil means the default host
 	}
 
 	Engine struct {
 		*RouterGroup
-		router        *router
-		groups        []*RouterGroup     // store all groups
-		htmlTemplates *template.Template // for html render
-		funcMap       template.FuncMap   // for html render
+		router   *routeTable
+		groups   []*RouterGroup   // store all groups
+		hosts    []*hostRouter    // virtual hosts, exact ones first
+		mu       sync.RWMutex     // guards groups and hosts, which may grow while serving
+		html     htmlRender       // for html render
+		funcMap  template.FuncMap // for html render
+		Upgrader *ws.Upgrader     // for WebSocket routes, nil means the default one
+		// JSONCodec encodes and decodes the JSON of the Context, nil means encoding/json
+		JSONCodec JSONCodec
+		// HTMLAutoReload parses templates again when their files change, by default in DebugMode
+		HTMLAutoReload AutoReload
+
+		// RedirectTrailingSlash redirects /foo/ to /foo if only the latter is registered, and vice versa,
+		// when false the mismatched path is served by the route as is
+		RedirectTrailingSlash bool
+		// RedirectFixedPath redirects to the cleaned path, e.g. //foo/../bar to /bar,
+		// and then tries to match static parts case-insensitively, e.g. /FOO to /foo
+		RedirectFixedPath bool
+		// UseRawPath routes by the escaped url.RawPath, so %2F in a param is not a separator
+		UseRawPath bool
+		// UnescapePathValues unescapes params matched from the raw path
+		UnescapePathValues bool
+		// TrustedProxies are the IPs or CIDRs whose X-Forwarded-For is trusted by c.ClientIP
+		TrustedProxies []string
+		// ShutdownDelay is waited by Shutdown after readiness fails, before the server
+		// stops accepting connections, so load balancers can notice
+		ShutdownDelay time.Duration
+		// Server configures the http.Server started by Run
+		Server ServerConfig
+
+		server       *http.Server // started by Run, guarded by mu
+		shuttingDown int32
 	}
 )
 
 // New is the constructor of tiny.Engine
 func New() *Engine {
-	engine := &Engine{router: newRouter()}
+	engine := &Engine{
+		router:                newRouteTable(),
+		RedirectTrailingSlash: true,
+		UnescapePathValues:    true,
+		Server:                DefaultServerConfig(),
+	}
 	engine.RouterGroup = &RouterGroup{engine: engine}
 	engine.groups = []*RouterGroup{engine.RouterGroup}
 	return engine
@@ -51,8 +90,15 @@ func (group *RouterGroup) Group(prefix string) *RouterGroup {
 		prefix: group.prefix + prefix,
 		parent: group,
 		engine: engine,
+		host:   group.host,
+	}
+	engine.mu.Lock()
+	defer engine.mu.Unlock()
+	if group.host != nil {
+		group.host.groups = append(group.host.groups, newGroup)
+	} else {
+		engine.groups = append(engine.groups, newGroup)
 	}
-	engine.groups = append(engine.groups, newGroup)
 	return newGroup
 }
 
@@ -61,20 +107,85 @@ func (group *RouterGroup) Use(middlewares ...HandlerFunc) {
 	group.middlewares = append(group.middlewares, middlewares...)
 }
 
-func (group *RouterGroup) addRoute(method string, comp string, handler HandlerFunc) {
+// routes returns the route table of the host of the group
+func (group *RouterGroup) routes() *routeTable {
+	if group.host != nil {
+		return group.host.router
+	}
+	return group.engine.router
+}
+
+// addRoute is safe to call while the engine is serving, handlers run after the group middlewares
+func (group *RouterGroup) addRoute(method string, comp string, handlers []HandlerFunc) *Route {
 	pattern := group.prefix + comp
-	log.Printf("Route %4s - %s", method, pattern)
-	group.engine.router.addRoute(method, pattern, handler)
+	if len(handlers) == 0 {
+		panic("tinyGin: no handler for route " + method + " " + pattern)
+	}
+	if group.host != nil {
+		debugPrint("Route %4s - %s%s", method, group.host.pattern, pattern)
+	} else {
+		debugPrint("Route %4s - %s", method, pattern)
+	}
+	return group.routes().addRoute(method, pattern, handlers...)
 }
 
-// GET defines the method to add GET request
-func (group *RouterGroup) GET(pattern string, handler HandlerFunc) {
-	group.addRoute("GET", pattern, handler)
+// RemoveRoute removes a route added by the group, it is safe to call while the engine is serving
+func (group *RouterGroup) RemoveRoute(method string, pattern string) bool {
+	return group.routes().removeRoute(method, group.prefix+pattern)
+}
+
+// GET defines the method to add GET request, the last handler usually writes the
+// response and the others are the middlewares of this route only
+func (group *RouterGroup) GET(pattern string, handlers ...HandlerFunc) *Route {
+	return group.addRoute(http.MethodGet, pattern, handlers)
 }
 
 // POST defines the method to add POST request
-func (group *RouterGroup) POST(pattern string, handler HandlerFunc) {
-	group.addRoute("POST", pattern, handler)
+func (group *RouterGroup) POST(pattern string, handlers ...HandlerFunc) *Route {
+	return group.addRoute(http.MethodPost, pattern, handlers)
+}
+
+// PUT defines the method to add PUT request
+func (group *RouterGroup) PUT(pattern string, handlers ...HandlerFunc) *Route {
+	return group.addRoute(http.MethodPut, pattern, handlers)
+}
+
+// PATCH defines the method to add PATCH request
+func (group *RouterGroup) PATCH(pattern string, handlers ...HandlerFunc) *Route {
+	return group.addRoute(http.MethodPatch, pattern, handlers)
+}
+
+// DELETE defines the method to add DELETE request
+func (group *RouterGroup) DELETE(pattern string, handlers ...HandlerFunc) *Route {
+	return group.addRoute(http.MethodDelete, pattern, handlers)
+}
+
+// HEAD defines the method to add HEAD request
+func (group *RouterGroup) HEAD(pattern string, handlers ...HandlerFunc) *Route {
+	return group.addRoute(http.MethodHead, pattern, handlers)
+}
+
+// OPTIONS defines the method to add OPTIONS request
+func (group *RouterGroup) OPTIONS(pattern string, handlers ...HandlerFunc) *Route {
+	return group.addRoute(http.MethodOptions, pattern, handlers)
+}
+
+// Handle defines the method to add request of any HTTP method
+func (group *RouterGroup) Handle(method string, pattern string, handlers ...HandlerFunc) *Route {
+	return group.addRoute(method, pattern, handlers)
+}
+
+var anyMethods = []string{
+	http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch,
+	http.MethodHead, http.MethodOptions, http.MethodDelete, http.MethodConnect,
+	http.MethodTrace,
+}
+
+// Any defines the method to add request of all HTTP methods
+func (group *RouterGroup) Any(pattern string, handlers ...HandlerFunc) {
+	for _, method := range anyMethods {
+		group.addRoute(method, pattern, handlers)
+	}
 }
 
 // create static handler
@@ -101,23 +212,60 @@ func (group *RouterGroup) Static(relativePath string, root string) {
 	group.GET(urlPattern, handler)
 }
 
-// SetFuncMap for custom render function
-func (engine *Engine) SetFuncMap(funcMap template.FuncMap) {
-	engine.funcMap = funcMap
+// Run defines the method to start a http server
+func (engine *Engine) Run(addr string) (err error) {
+	if IsDebugging() {
+		debugPrint("[WARNING] Running in debug mode, set %s=%s in production", EnvMode, ReleaseMode)
+		if len(engine.TrustedProxies) == 0 {
+			debugPrint("[WARNING] No trusted proxies, c.ClientIP() ignores X-Forwarded-For. Set TrustedProxies behind a proxy")
+		}
+		debugPrint("Listening and serving HTTP on %s", addr)
+	}
+	server := engine.NewServer(addr)
+	engine.mu.Lock()
+	engine.server = server
+	engine.mu.Unlock()
+	return server.ListenAndServe()
 }
 
-func (engine *Engine) LoadHTMLGlob(pattern string) {
-	engine.htmlTemplates = template.Must(template.New("").Funcs(engine.funcMap).ParseGlob(pattern))
+// Shutdown fails the readiness probe, waits ShutdownDelay and then gracefully shuts down
+// the server started by Run. Run returns http.ErrServerClosed
+func (engine *Engine) Shutdown(ctx context.Context) error {
+	atomic.StoreInt32(&engine.shuttingDown, 1)
+	if engine.ShutdownDelay > 0 {
+		select {
+		case <-time.After(engine.ShutdownDelay):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	engine.mu.RLock()
+	server := engine.server
+	engine.mu.RUnlock()
+	if server == nil {
+		return nil
+	}
+	return server.Shutdown(ctx)
 }
 
-// Run defines the method to start a http server
-func (engine *Engine) Run(addr string) (err error) {
-	return http.ListenAndServe(addr, engine)
+// ShuttingDown reports if Shutdown has been called
+func (engine *Engine) ShuttingDown() bool {
+	return atomic.LoadInt32(&engine.shuttingDown) == 1
 }
 
 func (engine *Engine) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	engine.mu.RLock()
+	r, groups := engine.router.load(), engine.groups
 	var middlewares []HandlerFunc
-	for _, group := range engine.groups {
+	h, hostParams := engine.matchHost(req.Host)
+	if h != nil {
+		// the middlewares of the engine apply to every host
+		r, groups = h.router.load(), h.groups
+		middlewares = append(middlewares, engine.RouterGroup.middlewares...)
+	}
+	engine.mu.RUnlock()
+
+	for _, group := range groups {
 		if strings.HasPrefix(req.URL.Path, group.prefix) {
 			middlewares = append(middlewares, group.middlewares...)
 		}
@@ -125,5 +273,6 @@ func (engine *Engine) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 	c := newContext(w, req)
 	c.handlers = middlewares
 	c.engine = engine
-	engine.router.handle(c)
+	c.Params = hostParams
+	r.handle(c)
 }
@@ -0,0 +1,214 @@
+// Package tinyGintest runs tinyGin handlers in process, without a real server
+package tinyGintest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	"tinyGin"
+	"tinyGin/internal/hooks"
+)
+
+// CreateTestContext returns a Context of a GET / writing to w and the Engine owning it,
+// replace c.Req or use CreateTestContextWithRequest to test other requests
+func CreateTestContext(w http.ResponseWriter) (*tinyGin.Context, *tinyGin.Engine) {
+	return CreateTestContextWithRequest(w, httptest.NewRequest(http.MethodGet, "/", nil))
+}
+
+// CreateTestContextWithRequest is CreateTestContext for the given request
+func CreateTestContextWithRequest(w http.ResponseWriter, req *http.Request) (*tinyGin.Context, *tinyGin.Engine) {
+	c, r := hooks.NewTestContext(w, req)
+	return c.(*tinyGin.Context), r.(*tinyGin.Engine)
+}
+
+// Request is a fluent builder of a request served by an Engine
+type Request struct {
+	engine  *tinyGin.Engine
+	method  string
+	path    string
+	query   url.Values
+	header  http.Header
+	cookies []*http.Cookie
+	body    io.Reader
+	err     error
+}
+
+// NewRequest starts building a request to engine
+func NewRequest(engine *tinyGin.Engine, method string, path string) *Request {
+	return &Request{engine: engine, method: method, path: path, query: url.Values{}, header: http.Header{}}
+}
+
+func GET(engine *tinyGin.Engine, path string) *Request {
+	return NewRequest(engine, http.MethodGet, path)
+}
+
+func POST(engine *tinyGin.Engine, path string) *Request {
+	return NewRequest(engine, http.MethodPost, path)
+}
+
+func (r *Request) Header(key string, value string) *Request {
+	r.header.Add(key, value)
+	return r
+}
+
+func (r *Request) Query(key string, value string) *Request {
+	r.query.Add(key, value)
+	return r
+}
+
+func (r *Request) Cookie(cookie *http.Cookie) *Request {
+	r.cookies = append(r.cookies, cookie)
+	return r
+}
+
+// Body sets a raw body
+func (r *Request) Body(body string) *Request {
+	r.body = strings.NewReader(body)
+	return r
+}
+
+// JSON sets obj encoded as JSON as the body
+func (r *Request) JSON(obj interface{}) *Request {
+	data, err := json.Marshal(obj)
+	if err != nil {
+		r.err = err
+		return r
+	}
+	r.body = bytes.NewReader(data)
+	r.header.Set("Content-Type", "application/json")
+	return r
+}
+
+// Form sets values as an url encoded form body
+func (r *Request) Form(values url.Values) *Request {
+	r.body = strings.NewReader(values.Encode())
+	r.header.Set("Content-Type", "application/x-www-form-urlencoded")
+	return r
+}
+
+// Build returns the http.Request
+func (r *Request) Build() (*http.Request, error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+	target := r.path
+	if len(r.query) > 0 {
+		sep := "?"
+		if strings.Contains(target, "?") {
+			sep = "&"
+		}
+		target += sep + r.query.Encode()
+	}
+	req := httptest.NewRequest(r.method, target, r.body)
+	for key, values := range r.header {
+		req.Header[key] = values
+	}
+	for _, cookie := range r.cookies {
+		req.AddCookie(cookie)
+	}
+	return req, nil
+}
+
+// Do serves the request by engine.ServeHTTP, it fails t if the request can't be built
+func (r *Request) Do(t testing.TB) *Response {
+	t.Helper()
+	req, err := r.Build()
+	if err != nil {
+		t.Fatalf("tinyGintest: failed to build request: %v", err)
+	}
+	w := httptest.NewRecorder()
+	r.engine.ServeHTTP(w, req)
+	return &Response{ResponseRecorder: w, t: t}
+}
+
+// Response is the recorded response with assertions, a failed assertion stops the test
+type Response struct {
+	*httptest.ResponseRecorder
+	t testing.TB
+}
+
+func (res *Response) Status(code int) *Response {
+	res.t.Helper()
+	if res.Code != code {
+		res.t.Fatalf("expect status %d, but got %d: %s", code, res.Code, res.Body.String())
+	}
+	return res
+}
+
+func (res *Response) Header(key string, value string) *Response {
+	res.t.Helper()
+	if got := res.Result().Header.Get(key); got != value {
+		res.t.Fatalf("expect header %s=%q, but got %q", key, value, got)
+	}
+	return res
+}
+
+func (res *Response) BodyContains(s string) *Response {
+	res.t.Helper()
+	if !strings.Contains(res.Body.String(), s) {
+		res.t.Fatalf("expect body to contain %q, but got %q", s, res.Body.String())
+	}
+	return res
+}
+
+// DecodeJSON decodes the body into v
+func (res *Response) DecodeJSON(v interface{}) *Response {
+	res.t.Helper()
+	if err := json.Unmarshal(res.Body.Bytes(), v); err != nil {
+		res.t.Fatalf("failed to decode json body %q: %v", res.Body.String(), err)
+	}
+	return res
+}
+
+// JSONField asserts the field at path of the JSON body, e.g. "user.name" or "items.0.id".
+// expected is compared after a JSON round trip, so numbers can be given as int
+func (res *Response) JSONField(path string, expected interface{}) *Response {
+	res.t.Helper()
+	var body interface{}
+	res.DecodeJSON(&body)
+	got, err := lookup(body, path)
+	if err != nil {
+		res.t.Fatalf("%v in %s", err, res.Body.String())
+	}
+	var want interface{}
+	data, _ := json.Marshal(expected)
+	_ = json.Unmarshal(data, &want)
+	if !reflect.DeepEqual(got, want) {
+		res.t.Fatalf("expect %s=%v, but got %v", path, want, got)
+	}
+	return res
+}
+
+func lookup(value interface{}, path string) (interface{}, error) {
+	if path == "" {
+		return value, nil
+	}
+	for _, key := range strings.Split(path, ".") {
+		switch v := value.(type) {
+		case map[string]interface{}:
+			field, ok := v[key]
+			if !ok {
+				return nil, fmt.Errorf("json field %s not found", path)
+			}
+			value = field
+		case []interface{}:
+			i, err := strconv.Atoi(key)
+			if err != nil || i < 0 || i >= len(v) {
+				return nil, fmt.Errorf("json index %s of %s out of range", key, path)
+			}
+			value = v[i]
+		default:
+			return nil, fmt.Errorf("json field %s not found", path)
+		}
+	}
+	return value, nil
+}
@@ -0,0 +1,46 @@
+package tinyGintest
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"tinyGin"
+)
+
+func TestRequest(t *testing.T) {
+	r := tinyGin.New()
+	r.POST("/users/:id", func(c *tinyGin.Context) {
+		var body map[string]interface{}
+		_ = json.NewDecoder(c.Req.Body).Decode(&body)
+		c.SetHeader("X-Trace", c.Req.Header.Get("X-Trace"))
+		c.JSON(http.StatusCreated, tinyGin.H{
+			"id":    c.Param("id"),
+			"page":  c.Query("page"),
+			"user":  body,
+			"roles": []string{"admin"},
+		})
+	})
+
+	POST(r, "/users/7").
+		Header("X-Trace", "abc").
+		Query("page", "2").
+		JSON(tinyGin.H{"name": "tom", "age": 18}).
+		Do(t).
+		Status(http.StatusCreated).
+		Header("X-Trace", "abc").
+		JSONField("id", "7").
+		JSONField("page", "2").
+		JSONField("user.age", 18).
+		JSONField("roles.0", "admin")
+}
+
+func TestCreateTestContext(t *testing.T) {
+	w := httptest.NewRecorder()
+	c, _ := CreateTestContextWithRequest(w, httptest.NewRequest("GET", "/hello?name=tom", nil))
+	c.String(http.StatusOK, "hello %s", c.Query("name"))
+	if w.Code != http.StatusOK || w.Body.String() != "hello tom" {
+		t.Fatalf("unexpected response %d %q", w.Code, w.Body.String())
+	}
+}
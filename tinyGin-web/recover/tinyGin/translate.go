@@ -0,0 +1,17 @@
+package tinyGin
+
+// Translator translates the messages of a request, it is set by an i18n middleware
+type Translator interface {
+	Translate(key string, args ...interface{}) string
+}
+
+// TranslatorKey is the key of the Translator of a request, set by c.Set
+const TranslatorKey = "tinyGin/translator"
+
+// T translates key with the Translator of the request, key itself is returned without one
+func (c *Context) T(key string, args ...interface{}) string {
+	if t, ok := c.Keys[TranslatorKey].(Translator); ok {
+		return t.Translate(key, args...)
+	}
+	return key
+}
@@ -6,10 +6,12 @@ import (
 )
 
 type node struct {
-	pattern  string  // 待匹配路由
-	part     string  // 路由中的一部分
-	children []*node // 子节点
-	isWild   bool    // 是否精确匹配
+	pattern  string            // 待匹配路由
+	slashAlt string            // 只有末尾斜杠不同的另一个路由, 例如 /foo 与 /foo/ 共用一个节点
+	part     string            // 路由中的一部分
+	children []*node           // 子节点
+	isWild   bool              // 是否精确匹配
+	check    func(string) bool // 参数约束, 例如 :id<int>
 }
 
 func (n *node) String() string {
@@ -18,6 +20,10 @@ func (n *node) String() string {
 
 func (n *node) insert(pattern string, parts []string, height int) {
 	if len(parts) == height {
+		if n.pattern != "" && hasTrailingSlash(n.pattern) != hasTrailingSlash(pattern) {
+			n.slashAlt = pattern
+			return
+		}
 		n.pattern = pattern
 		return
 	}
@@ -25,12 +31,51 @@ func (n *node) insert(pattern string, parts []string, height int) {
 	part := parts[height]
 	child := n.matchChild(part)
 	if child == nil {
-		child = &node{part: part, isWild: part[0] == ':' || part[0] == '*'}
+		child = &node{part: part, isWild: part[0] == ':' || part[0] == '*', check: paramCheck(part)}
 		n.children = append(n.children, child)
 	}
 	child.insert(pattern, parts, height+1)
 }
 
+// remove 删除 pattern 并清理没有路由的叶子节点, 返回 n 是否可以被删除
+func (n *node) remove(pattern string, parts []string, height int) bool {
+	if len(parts) == height {
+		if n.pattern == pattern {
+			n.pattern, n.slashAlt = n.slashAlt, ""
+		} else if n.slashAlt == pattern {
+			n.slashAlt = ""
+		}
+	} else if child := n.matchChild(parts[height]); child != nil {
+		if child.remove(pattern, parts, height+1) {
+			for i, c := range n.children {
+				if c == child {
+					n.children = append(n.children[:i:i], n.children[i+1:]...)
+					break
+				}
+			}
+		}
+	}
+	return n.pattern == "" && len(n.children) == 0
+}
+
+// clone 深拷贝, 用于写时复制
+func (n *node) clone() *node {
+	c := *n
+	c.children = make([]*node, len(n.children))
+	for i, child := range n.children {
+		c.children[i] = child.clone()
+	}
+	return &c
+}
+
+// patternFor 返回与 path 末尾斜杠一致的路由, 没有则返回 pattern
+func (n *node) patternFor(path string) string {
+	if n.slashAlt != "" && hasTrailingSlash(n.slashAlt) == hasTrailingSlash(path) {
+		return n.slashAlt
+	}
+	return n.pattern
+}
+
 func (n *node) search(parts []string, height int) *node {
 	if len(parts) == height || strings.HasPrefix(n.part, "*") {
 		if n.pattern == "" {
@@ -61,10 +106,11 @@ func (n *node) travel(list *[]*node) {
 	}
 }
 
-// matchChild 第一个匹配成功的节点，用于插入
+// matchChild 完全相同的节点，用于插入
+// :id<int> 与 :name 是不同的节点, 可以共存
 func (n *node) matchChild(part string) *node {
 	for _, child := range n.children {
-		if child.part == part || child.isWild {
+		if child.part == part {
 			return child
 		}
 	}
@@ -72,12 +118,26 @@ func (n *node) matchChild(part string) *node {
 }
 
 // matchChildren 所有匹配成功的节点，用于查找
+// 按优先级排序: 静态节点, 有约束的参数, 无约束的参数, 通配符
 func (n *node) matchChildren(part string) []*node {
-	nodes := make([]*node, 0)
+	var static, constrained, param, catchAll []*node
 	for _, child := range n.children {
-		if child.part == part || child.isWild {
-			nodes = append(nodes, child)
+		switch {
+		case !child.isWild:
+			if child.part == part {
+				static = append(static, child)
+			}
+		case child.part[0] == '*':
+			catchAll = append(catchAll, child)
+		case child.check != nil:
+			if child.check(part) {
+				constrained = append(constrained, child)
+			}
+		default:
+			param = append(param, child)
 		}
 	}
-	return nodes
+	nodes := append(static, constrained...)
+	nodes = append(nodes, param...)
+	return append(nodes, catchAll...)
 }
@@ -0,0 +1,60 @@
+package tinyGin
+
+import (
+	"net/http"
+	"reflect"
+)
+
+// StatusCoder is implemented by the responses of Typed handlers answering another status than 200
+type StatusCoder interface {
+	StatusCode() int
+}
+
+// Typed adapts a handler with a typed request and response. The request is bound by c.Bind,
+// from the body, the query and the route params, and validated. The response is written
+// by c.Negotiate. Errors are written as problems, mapped like ErrorHandler does, with the
+// mappings of an ErrorHandler running before.
+// It panics if the validate tags of In are invalid, so register custom rules before
+func Typed[In any, Out any](handler func(c *Context, req In) (Out, error)) HandlerFunc {
+	if err := checkValidation(reflect.TypeOf((*In)(nil)).Elem()); err != nil {
+		panic(err)
+	}
+	return func(c *Context) {
+		var req In
+		if err := bindTyped(c, &req); err != nil {
+			c.writeError(err)
+			return
+		}
+		out, err := handler(c, req)
+		if err != nil {
+			c.writeError(err)
+			return
+		}
+		code := http.StatusOK
+		if s, ok := any(out).(StatusCoder); ok {
+			code = s.StatusCode()
+		}
+		if code == http.StatusNoContent {
+			c.Status(code)
+			return
+		}
+		c.Negotiate(code, out)
+	}
+}
+
+// bindTyped binds ptr, a pointer to the request, allocating it if the request is a pointer
+func bindTyped(c *Context, ptr interface{}) error {
+	v := reflect.ValueOf(ptr).Elem()
+	if v.Kind() == reflect.Ptr {
+		v.Set(reflect.New(v.Type().Elem()))
+		ptr = v.Interface()
+		v = v.Elem()
+	}
+	if v.Kind() == reflect.Struct {
+		return c.Bind(ptr)
+	}
+	if err := c.BindBody(ptr); err != nil {
+		return err
+	}
+	return Validate(ptr)
+}
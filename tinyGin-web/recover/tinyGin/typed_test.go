@@ -0,0 +1,80 @@
+package tinyGin
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type createItem struct {
+	Shop  string `uri:"shop"`
+	Name  string `json:"name" validate:"required"`
+	Price int    `json:"price" validate:"min=1"`
+}
+
+type item struct {
+	Shop  string `json:"shop" xml:"shop"`
+	Name  string `json:"name" xml:"name"`
+	Price int    `json:"price" xml:"price"`
+}
+
+type created struct {
+	item
+}
+
+func (created) StatusCode() int { return http.StatusCreated }
+
+var errSoldOut = errors.New("sold out")
+
+func TestTyped(t *testing.T) {
+	r := New()
+	r.Use(ErrorHandler(ErrorMapping{Is: errSoldOut, Status: http.StatusConflict}))
+	r.POST("/shops/:shop/items", Typed(func(c *Context, req *createItem) (created, error) {
+		if req.Name == "tea" {
+			return created{}, errSoldOut
+		}
+		return created{item{Shop: req.Shop, Name: req.Name, Price: req.Price}}, nil
+	}))
+	r.GET("/items", Typed(func(c *Context, req struct{}) ([]item, error) {
+		return []item{{Shop: "corner", Name: "coffee", Price: 3}}, nil
+	}))
+	r.GET("/fail", Typed(func(c *Context, req struct{}) (*item, error) {
+		return nil, context.DeadlineExceeded
+	}))
+
+	cases := []struct {
+		method      string
+		target      string
+		body        string
+		accept      string
+		code        int
+		contentType string
+		response    string
+	}{
+		{"POST", "/shops/corner/items", `{"name": "coffee", "price": 3}`, "", 201, "application/json",
+			`{"shop":"corner","name":"coffee","price":3}`},
+		{"POST", "/shops/corner/items", `{"name": "coffee", "price": -1}`, "", 422, "application/problem+json",
+			`"errors":{"price":"price must be at least 1"}`},
+		{"POST", "/shops/corner/items", `{"name": "coffee", "price": "3"}`, "", 400, "application/problem+json", `"status":400`},
+		{"POST", "/shops/corner/items", `{"name": "tea", "price": 3}`, "", 409, "application/problem+json", "sold out"},
+		{"GET", "/items", "", "application/xml;q=0.9, application/json;q=0.5", 200, "application/xml; charset=utf-8",
+			"<item><shop>corner</shop><name>coffee</name><price>3</price></item>"},
+		{"GET", "/items", "", "application/*", 200, "application/json", `[{"shop":"corner"`},
+		{"GET", "/items", "", "text/html, application/yaml;q=0.8", 200, "application/yaml", "- name: coffee\n  price: 3\n  shop: corner\n"},
+		{"GET", "/items", "", "text/html", 406, "application/problem+json", `"status":406`},
+		{"GET", "/fail", "", "", 500, "application/problem+json", `"status":500`},
+	}
+	for _, tc := range cases {
+		req := httptest.NewRequest(tc.method, tc.target, strings.NewReader(tc.body))
+		req.Header.Set("Accept", tc.accept)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		if w.Code != tc.code || w.Header().Get("Content-Type") != tc.contentType || !strings.Contains(w.Body.String(), tc.response) {
+			t.Fatalf("%s %s %s: expect %d %s %q, but got %d %s %q", tc.method, tc.target, tc.accept,
+				tc.code, tc.contentType, tc.response, w.Code, w.Header().Get("Content-Type"), w.Body.String())
+		}
+	}
+}
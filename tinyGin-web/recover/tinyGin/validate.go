@@ -0,0 +1,335 @@
+package tinyGin
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// FieldError is a failed validation rule of a field
+type FieldError struct {
+	Field   string // the json name of the field, nested fields are joined by dots, e.g. items[0].name
+	Form    string // the form tag of a top level field, the name BindForm binds it by
+	Rule    string // e.g. required or min
+	Param   string // e.g. 3 for min=3
+	Message string
+}
+
+func (e *FieldError) Error() string {
+	return e.Message
+}
+
+// ValidationErrors are the field errors of a validated value
+type ValidationErrors []*FieldError
+
+func (errs ValidationErrors) Error() string {
+	messages := make([]string, len(errs))
+	for i, err := range errs {
+		messages[i] = err.Message
+	}
+	return strings.Join(messages, "; ")
+}
+
+// Map returns the first message of every field, keyed by the field name
+func (errs ValidationErrors) Map() map[string]string {
+	m := make(map[string]string, len(errs))
+	for _, err := range errs {
+		if _, ok := m[err.Field]; !ok {
+			m[err.Field] = err.Message
+		}
+	}
+	return m
+}
+
+// Validator is implemented by the values with validation beyond the validate tags,
+// Validate is called after the tags passed
+type Validator interface {
+	Validate() error
+}
+
+// ValidationRule reports if v is valid for the rule param
+type ValidationRule func(v reflect.Value, param string) bool
+
+var emailPattern = regexp.MustCompile(`^[^@\s]+@[^@\s]+\.[^@\s]+$`)
+
+// validationRules are the rules of the validate tag, e.g. validate:"required,min=3"
+var validationRules = struct {
+	sync.RWMutex
+	rules    map[string]ValidationRule
+	messages map[string]string
+}{
+	rules: map[string]ValidationRule{
+		"required": func(v reflect.Value, _ string) bool { return !v.IsZero() },
+		"min": func(v reflect.Value, param string) bool {
+			n, ok := compare(v, param)
+			return ok && n >= 0
+		},
+		"max": func(v reflect.Value, param string) bool {
+			n, ok := compare(v, param)
+			return ok && n <= 0
+		},
+		"len": func(v reflect.Value, param string) bool {
+			n, ok := compare(v, param)
+			return ok && n == 0
+		},
+		"email": func(v reflect.Value, _ string) bool { return emailPattern.MatchString(v.String()) },
+		"oneof": func(v reflect.Value, param string) bool {
+			s := fmt.Sprint(v.Interface())
+			for _, option := range strings.Fields(param) {
+				if s == option {
+					return true
+				}
+			}
+			return false
+		},
+	},
+	messages: map[string]string{
+		"required": "{field} is required",
+		"min":      "{field} must be at least {param}",
+		"max":      "{field} must be at most {param}",
+		"len":      "{field} must have a length of {param}",
+		"email":    "{field} must be an email address",
+		"oneof":    "{field} must be one of {param}",
+	},
+}
+
+// RegisterValidation adds a rule usable in the validate tag, {field} and {param} in message
+// are replaced, e.g. "{field} must be a slug"
+func RegisterValidation(name string, rule ValidationRule, message string) {
+	validationRules.Lock()
+	defer validationRules.Unlock()
+	validationRules.rules[name] = rule
+	validationRules.messages[name] = message
+}
+
+// comparableKinds are the kinds accepted by min, max and len
+var comparableKinds = map[reflect.Kind]bool{
+	reflect.String: true, reflect.Slice: true, reflect.Map: true, reflect.Array: true,
+	reflect.Int: true, reflect.Int8: true, reflect.Int16: true, reflect.Int32: true, reflect.Int64: true,
+	reflect.Uint: true, reflect.Uint8: true, reflect.Uint16: true, reflect.Uint32: true, reflect.Uint64: true,
+	reflect.Float32: true, reflect.Float64: true,
+}
+
+// compare compares a number with param, or the length of a string, slice or map.
+// False is returned for another kind or a bad param, checkValidation rejects them up front
+func compare(v reflect.Value, param string) (int, bool) {
+	var n float64
+	switch v.Kind() {
+	case reflect.String:
+		n = float64(utf8.RuneCountInString(v.String()))
+	case reflect.Slice, reflect.Map, reflect.Array:
+		n = float64(v.Len())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n = float64(v.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n = float64(v.Uint())
+	case reflect.Float32, reflect.Float64:
+		n = v.Float()
+	default:
+		return 0, false
+	}
+	p, err := strconv.ParseFloat(param, 64)
+	if err != nil {
+		return 0, false
+	}
+	switch {
+	case n < p:
+		return -1, true
+	case n > p:
+		return 1, true
+	}
+	return 0, true
+}
+
+// validationCheck is the cached result of checkValidation
+type validationCheck struct {
+	err error
+}
+
+var checkedTypes sync.Map // reflect.Type -> validationCheck
+
+// checkValidation checks the validate tags of t and its nested structs once per type:
+// every rule must be registered, and min, max and len need a number param and a field
+// they can compare. Typed calls it when the handler is registered
+func checkValidation(t reflect.Type) error {
+	if t == nil {
+		return nil
+	}
+	if check, ok := checkedTypes.Load(t); ok {
+		return check.(validationCheck).err
+	}
+	err := checkTags(t, make(map[reflect.Type]bool))
+	checkedTypes.Store(t, validationCheck{err: err})
+	return err
+}
+
+func checkTags(t reflect.Type, seen map[reflect.Type]bool) error {
+	for t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice || t.Kind() == reflect.Array {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct || seen[t] {
+		return nil
+	}
+	seen[t] = true
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.PkgPath != "" {
+			continue
+		}
+		if tag := f.Tag.Get("validate"); tag != "" {
+			if err := checkTag(f.Type, tag); err != nil {
+				return fmt.Errorf("tinyGin: validate tag of %s.%s: %v", t, f.Name, err)
+			}
+		}
+		if err := checkTags(f.Type, seen); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func checkTag(t reflect.Type, tag string) error {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	validationRules.RLock()
+	defer validationRules.RUnlock()
+	for _, rule := range strings.Split(tag, ",") {
+		ruleName, param := splitRule(rule)
+		if _, ok := validationRules.rules[ruleName]; !ok {
+			return fmt.Errorf("unknown rule %s", ruleName)
+		}
+		switch ruleName {
+		case "min", "max", "len":
+			if !comparableKinds[t.Kind()] {
+				return fmt.Errorf("%s does not apply to %s", ruleName, t)
+			}
+			if _, err := strconv.ParseFloat(param, 64); err != nil {
+				return fmt.Errorf("bad param %q of %s", param, ruleName)
+			}
+		}
+	}
+	return nil
+}
+
+// splitRule splits a rule such as min=3 into its name and param
+func splitRule(rule string) (string, string) {
+	if i := strings.IndexByte(rule, '='); i >= 0 {
+		return rule[:i], rule[i+1:]
+	}
+	return rule, ""
+}
+
+// Validate checks the validate tags of obj, a struct or a pointer to struct, and then
+// calls its Validate method. Failed tags are returned as ValidationErrors, a tag with an
+// unknown rule or a rule which does not apply to its field is an error of its own
+func Validate(obj interface{}) error {
+	if err := checkValidation(reflect.TypeOf(obj)); err != nil {
+		return err
+	}
+	var errs ValidationErrors
+	validateValue(reflect.ValueOf(obj), "", &errs)
+	if len(errs) > 0 {
+		return errs
+	}
+	if v, ok := obj.(Validator); ok {
+		return v.Validate()
+	}
+	return nil
+}
+
+func validateValue(v reflect.Value, path string, errs *ValidationErrors) {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return
+		}
+		v = v.Elem()
+	}
+	switch v.Kind() {
+	case reflect.Struct:
+		validateStruct(v, path, errs)
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			validateValue(v.Index(i), fmt.Sprintf("%s[%d]", path, i), errs)
+		}
+	}
+}
+
+func validateStruct(v reflect.Value, path string, errs *ValidationErrors) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.PkgPath != "" {
+			continue
+		}
+		field := v.Field(i)
+		if f.Anonymous {
+			validateValue(field, path, errs)
+			continue
+		}
+		name := f.Name
+		if tag := strings.Split(f.Tag.Get("json"), ",")[0]; tag != "" && tag != "-" {
+			name = tag
+		}
+		if path != "" {
+			name = path + "." + name
+		}
+
+		if tag := f.Tag.Get("validate"); tag != "" {
+			if err := checkRules(field, name, tag); err != nil {
+				if form := strings.Split(f.Tag.Get("form"), ",")[0]; path == "" && form != "-" {
+					err.Form = form
+				}
+				*errs = append(*errs, err)
+				continue
+			}
+		}
+		validateValue(field, name, errs)
+	}
+}
+
+// checkRules returns the first failed rule of tag, the other rules are skipped
+// for a zero value which is not required
+func checkRules(field reflect.Value, name string, tag string) *FieldError {
+	rules := strings.Split(tag, ",")
+	required := false
+	for _, rule := range rules {
+		required = required || rule == "required"
+	}
+	value := field
+	for value.Kind() == reflect.Ptr && !value.IsNil() {
+		value = value.Elem()
+	}
+	// a nil pointer or a zero value is valid unless required
+	if !required && (value.Kind() == reflect.Ptr || value.IsZero()) {
+		return nil
+	}
+
+	validationRules.RLock()
+	defer validationRules.RUnlock()
+	for _, rule := range rules {
+		ruleName, param := splitRule(rule)
+		check, ok := validationRules.rules[ruleName]
+		if !ok {
+			// only reachable through an interface field, whose type was not checked
+			check = func(reflect.Value, string) bool { return false }
+		}
+		target := value
+		if ruleName == "required" {
+			target = field
+		}
+		if !check(target, param) {
+			return &FieldError{
+				Field:   name,
+				Rule:    ruleName,
+				Param:   param,
+				Message: strings.NewReplacer("{field}", name, "{param}", param).Replace(validationRules.messages[ruleName]),
+			}
+		}
+	}
+	return nil
+}
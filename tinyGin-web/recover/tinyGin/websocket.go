@@ -0,0 +1,27 @@
+package tinyGin
+
+import (
+	"net/http"
+
+	"tinyGin/ws"
+)
+
+// WS defines the method to add a WebSocket route, the GET request is upgraded
+// by engine.Upgrader and the connection is closed once handler returns
+func (group *RouterGroup) WS(pattern string, handler func(*Context, *ws.Conn)) {
+	group.GET(pattern, func(c *Context) {
+		upgrader := c.engine.Upgrader
+		if upgrader == nil {
+			upgrader = &ws.Upgrader{}
+		}
+		conn, err := upgrader.Upgrade(c.Writer, c.Req, nil)
+		if err != nil {
+			// Upgrade has replied the error
+			return
+		}
+		defer conn.Close()
+
+		c.StatusCode = http.StatusSwitchingProtocols
+		handler(c, conn)
+	})
+}
@@ -0,0 +1,32 @@
+package tinyGin
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"tinyGin/ws"
+)
+
+func TestRouterGroup_WS(t *testing.T) {
+	r := New()
+	r.Group("/chat").WS("/:room", func(c *Context, conn *ws.Conn) {
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			return
+		}
+		_ = conn.WriteMessage(ws.TextMessage, []byte(c.Param("room")+": "+string(data)))
+	})
+	ts := httptest.NewServer(r)
+	defer ts.Close()
+
+	conn, _, err := ws.Dial("ws"+strings.TrimPrefix(ts.URL, "http")+"/chat/go", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	_ = conn.WriteMessage(ws.TextMessage, []byte("hello"))
+	if _, data, err := conn.ReadMessage(); err != nil || string(data) != "go: hello" {
+		t.Fatalf("unexpected reply %q %v", data, err)
+	}
+}
@@ -0,0 +1,293 @@
+package ws
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// close codes of RFC 6455 section 7.4.1
+const (
+	CloseNormalClosure           = 1000
+	CloseGoingAway               = 1001
+	CloseProtocolError           = 1002
+	CloseUnsupportedData         = 1003
+	CloseNoStatusReceived        = 1005
+	CloseAbnormalClosure         = 1006
+	CloseInvalidFramePayloadData = 1007
+	ClosePolicyViolation         = 1008
+	CloseMessageTooBig           = 1009
+	CloseMandatoryExtension      = 1010
+	CloseInternalServerErr       = 1011
+)
+
+// DefaultReadLimit is the ReadLimit of new connections
+const DefaultReadLimit = 32 << 20
+
+// ErrCloseSent is returned when writing after the close frame was sent
+var ErrCloseSent = errors.New("ws: close sent")
+
+// CloseError is returned by ReadMessage when the connection is closed by a close frame
+type CloseError struct {
+	Code int
+	Text string
+}
+
+func (e *CloseError) Error() string {
+	return fmt.Sprintf("ws: close %d %s", e.Code, e.Text)
+}
+
+// Conn is a message oriented WebSocket connection.
+// ReadMessage must be called from one goroutine, the write methods are safe for concurrent use
+type Conn struct {
+	conn     net.Conn
+	br       *bufio.Reader
+	isServer bool
+	subproto string
+
+	// ReadLimit is the max size of a message in bytes, NewConn sets DefaultReadLimit, 0 means no limit
+	ReadLimit int64
+	// FragmentSize splits written messages into frames of at most this many bytes, 0 means no split
+	FragmentSize int
+	// PingHandler is called for every ping, the default one replies a pong
+	PingHandler func(data []byte) error
+	// PongHandler is called for every pong, the default one does nothing
+	PongHandler func(data []byte) error
+
+	wmu       sync.Mutex
+	closeSent bool
+}
+
+// NewConn wraps an established connection, br may hold bytes already read from conn.
+// Frames sent by clients are masked, isServer decides which side conn is
+func NewConn(conn net.Conn, br *bufio.Reader, isServer bool) *Conn {
+	if br == nil {
+		br = bufio.NewReader(conn)
+	}
+	c := &Conn{conn: conn, br: br, isServer: isServer, ReadLimit: DefaultReadLimit}
+	c.PingHandler = func(data []byte) error {
+		err := c.WriteControl(PongMessage, data)
+		if err == ErrCloseSent {
+			return nil
+		}
+		return err
+	}
+	c.PongHandler = func([]byte) error { return nil }
+	return c
+}
+
+// Subprotocol returns the negotiated subprotocol
+func (c *Conn) Subprotocol() string {
+	return c.subproto
+}
+
+func (c *Conn) LocalAddr() net.Addr {
+	return c.conn.LocalAddr()
+}
+
+func (c *Conn) RemoteAddr() net.Addr {
+	return c.conn.RemoteAddr()
+}
+
+func (c *Conn) SetReadDeadline(t time.Time) error {
+	return c.conn.SetReadDeadline(t)
+}
+
+func (c *Conn) SetWriteDeadline(t time.Time) error {
+	return c.conn.SetWriteDeadline(t)
+}
+
+// ReadMessage returns the next text or binary message, fragments are joined and
+// control frames are handled in between. A *CloseError is returned when the peer closes
+func (c *Conn) ReadMessage() (messageType int, data []byte, err error) {
+	for {
+		f, err := readFrame(c.br, c.frameLimit(len(data)))
+		if err == errFrameTooLarge {
+			return 0, nil, c.fail(CloseMessageTooBig, "message too big")
+		}
+		if err == errControlTooLarge {
+			return 0, nil, c.fail(CloseProtocolError, "bad control frame")
+		}
+		if err != nil {
+			return 0, nil, err
+		}
+		if f.rsv != 0 {
+			return 0, nil, c.fail(CloseProtocolError, "unexpected reserved bits")
+		}
+		if f.masked != c.isServer {
+			return 0, nil, c.fail(CloseProtocolError, "bad frame masking")
+		}
+		if isControl(f.opcode) && (!f.fin || len(f.payload) > maxControlPayload) {
+			return 0, nil, c.fail(CloseProtocolError, "bad control frame")
+		}
+
+		switch f.opcode {
+		case PingMessage:
+			if err := c.PingHandler(f.payload); err != nil {
+				return 0, nil, err
+			}
+		case PongMessage:
+			if err := c.PongHandler(f.payload); err != nil {
+				return 0, nil, err
+			}
+		case CloseMessage:
+			return 0, nil, c.handleClose(f.payload)
+		case TextMessage, BinaryMessage:
+			if messageType != 0 {
+				return 0, nil, c.fail(CloseProtocolError, "expect a continuation frame")
+			}
+			messageType, data = int(f.opcode), f.payload
+		case continuationFrame:
+			if messageType == 0 {
+				return 0, nil, c.fail(CloseProtocolError, "unexpected continuation frame")
+			}
+			data = append(data, f.payload...)
+		default:
+			return 0, nil, c.fail(CloseProtocolError, "unknown opcode")
+		}
+
+		if c.ReadLimit > 0 && int64(len(data)) > c.ReadLimit {
+			return 0, nil, c.fail(CloseMessageTooBig, "message too big")
+		}
+		if messageType != 0 && f.fin && !isControl(f.opcode) {
+			if messageType == TextMessage && !utf8.Valid(data) {
+				return 0, nil, c.fail(CloseInvalidFramePayloadData, "invalid utf-8 text")
+			}
+			return messageType, data, nil
+		}
+	}
+}
+
+// frameLimit returns the max payload of the next frame when buffered bytes of the
+// message are already read, -1 means no limit
+func (c *Conn) frameLimit(buffered int) int64 {
+	if c.ReadLimit <= 0 {
+		return -1
+	}
+	return c.ReadLimit - int64(buffered)
+}
+
+// handleClose validates the close frame of the peer and echoes it
+func (c *Conn) handleClose(payload []byte) error {
+	code, text := CloseNoStatusReceived, ""
+	switch {
+	case len(payload) == 1:
+		return c.fail(CloseProtocolError, "bad close frame")
+	case len(payload) >= 2:
+		code = int(binary.BigEndian.Uint16(payload))
+		text = string(payload[2:])
+		if !validCloseCode(code) {
+			return c.fail(CloseProtocolError, "bad close code")
+		}
+		if !utf8.ValidString(text) {
+			return c.fail(CloseInvalidFramePayloadData, "invalid utf-8 close reason")
+		}
+	}
+
+	echo := code
+	if echo == CloseNoStatusReceived {
+		echo = CloseNormalClosure
+	}
+	if err := c.WriteClose(echo, ""); err != nil && err != ErrCloseSent {
+		return err
+	}
+	return &CloseError{Code: code, Text: text}
+}
+
+func validCloseCode(code int) bool {
+	switch {
+	case code >= 3000 && code <= 4999:
+		return true
+	case code < 1000 || code > 1011:
+		return false
+	}
+	return code != 1004 && code != CloseNoStatusReceived && code != CloseAbnormalClosure
+}
+
+// fail sends a close frame for a protocol violation of the peer and returns the error
+func (c *Conn) fail(code int, text string) error {
+	_ = c.WriteClose(code, text)
+	return &CloseError{Code: code, Text: text}
+}
+
+// WriteMessage writes a text or binary message, split into frames of FragmentSize
+func (c *Conn) WriteMessage(messageType int, data []byte) error {
+	if messageType != TextMessage && messageType != BinaryMessage {
+		return c.WriteControl(messageType, data)
+	}
+
+	c.wmu.Lock()
+	defer c.wmu.Unlock()
+	if c.closeSent {
+		return ErrCloseSent
+	}
+	opcode := byte(messageType)
+	for {
+		chunk := data
+		if c.FragmentSize > 0 && len(chunk) > c.FragmentSize {
+			chunk = data[:c.FragmentSize]
+		}
+		data = data[len(chunk):]
+		fin := len(data) == 0
+		if err := writeFrame(c.conn, fin, opcode, !c.isServer, chunk); err != nil {
+			return err
+		}
+		if fin {
+			return nil
+		}
+		opcode = continuationFrame
+	}
+}
+
+// WriteControl writes a ping, pong or close frame
+func (c *Conn) WriteControl(messageType int, data []byte) error {
+	if !isControl(byte(messageType)) {
+		return errors.New("ws: bad control message type")
+	}
+	if len(data) > maxControlPayload {
+		return errors.New("ws: control payload too large")
+	}
+
+	c.wmu.Lock()
+	defer c.wmu.Unlock()
+	if c.closeSent {
+		return ErrCloseSent
+	}
+	if messageType == CloseMessage {
+		c.closeSent = true
+	}
+	return writeFrame(c.conn, true, byte(messageType), !c.isServer, data)
+}
+
+// Ping sends a ping, the pong arrives at PongHandler
+func (c *Conn) Ping(data []byte) error {
+	return c.WriteControl(PingMessage, data)
+}
+
+// WriteClose sends a close frame, no message can be written afterwards
+func (c *Conn) WriteClose(code int, text string) error {
+	payload := make([]byte, 2, 2+len(text))
+	binary.BigEndian.PutUint16(payload, uint16(code))
+	payload = append(payload, text...)
+	return c.WriteControl(CloseMessage, payload)
+}
+
+// Close sends a normal close frame if none was sent and closes the connection
+func (c *Conn) Close() error {
+	if err := c.WriteClose(CloseNormalClosure, ""); err == nil {
+		// give the peer a moment to answer the closing handshake
+		_ = c.conn.SetReadDeadline(time.Now().Add(time.Second))
+		for {
+			f, err := readFrame(c.br, c.frameLimit(0))
+			if err != nil || f.opcode == CloseMessage {
+				break
+			}
+		}
+	}
+	return c.conn.Close()
+}
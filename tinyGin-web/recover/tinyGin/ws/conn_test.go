@@ -0,0 +1,170 @@
+package ws
+
+import (
+	"bytes"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// pipe returns a server and client Conn over an in-process loopback connection
+func pipe() (server *Conn, client *Conn) {
+	s, c := net.Pipe()
+	return NewConn(s, nil, true), NewConn(c, nil, false)
+}
+
+func TestConn_Fragmentation(t *testing.T) {
+	server, client := pipe()
+	client.FragmentSize = 3
+	go client.WriteMessage(TextMessage, []byte("hello tinyGin"))
+
+	messageType, data, err := server.ReadMessage()
+	if err != nil || messageType != TextMessage || string(data) != "hello tinyGin" {
+		t.Fatalf("unexpected message %d %q %v", messageType, data, err)
+	}
+}
+
+func TestConn_LargeMessage(t *testing.T) {
+	server, client := pipe()
+	payload := bytes.Repeat([]byte("x"), 70000) // needs the 64 bit length
+	go server.WriteMessage(BinaryMessage, payload)
+
+	messageType, data, err := client.ReadMessage()
+	if err != nil || messageType != BinaryMessage || !bytes.Equal(data, payload) {
+		t.Fatalf("unexpected message %d %d %v", messageType, len(data), err)
+	}
+}
+
+func TestConn_PingPong(t *testing.T) {
+	server, client := pipe()
+	pong := make(chan string, 1)
+	client.PongHandler = func(data []byte) error {
+		pong <- string(data)
+		return nil
+	}
+
+	// the server answers the ping while reading the next message
+	go server.ReadMessage()
+	go client.ReadMessage()
+	if err := client.Ping([]byte("tiny")); err != nil {
+		t.Fatal(err)
+	}
+	if data := <-pong; data != "tiny" {
+		t.Fatalf("expect pong tiny, but got %q", data)
+	}
+}
+
+func TestConn_Close(t *testing.T) {
+	server, client := pipe()
+	echo := make(chan error, 1)
+	go func() {
+		_, _, err := client.ReadMessage()
+		echo <- err
+	}()
+
+	go server.ReadMessage() // receives the echo
+	go server.WriteClose(CloseGoingAway, "bye")
+	err := <-echo
+	if ce, ok := err.(*CloseError); !ok || ce.Code != CloseGoingAway || ce.Text != "bye" {
+		t.Fatalf("expect close 1001, but got %v", err)
+	}
+	if err := client.WriteMessage(TextMessage, []byte("late")); err != ErrCloseSent {
+		t.Fatalf("expect ErrCloseSent, but got %v", err)
+	}
+}
+
+func TestConn_ProtocolError(t *testing.T) {
+	// two servers: frames are not masked, which a server must reject
+	s, c := net.Pipe()
+	server, peer := NewConn(s, nil, true), NewConn(c, nil, true)
+	go peer.ReadMessage()
+	go peer.WriteMessage(TextMessage, []byte("unmasked"))
+
+	_, _, err := server.ReadMessage()
+	if ce, ok := err.(*CloseError); !ok || ce.Code != CloseProtocolError {
+		t.Fatalf("expect close 1002, but got %v", err)
+	}
+}
+
+func TestConn_ReadLimit(t *testing.T) {
+	server, client := pipe()
+	server.ReadLimit = 4
+	client.FragmentSize = 2
+	go client.ReadMessage()
+	go client.WriteMessage(BinaryMessage, []byte("too long"))
+
+	_, _, err := server.ReadMessage()
+	if ce, ok := err.(*CloseError); !ok || ce.Code != CloseMessageTooBig {
+		t.Fatalf("expect close 1009, but got %v", err)
+	}
+}
+
+func TestUpgrade(t *testing.T) {
+	upgrader := &Upgrader{Subprotocols: []string{"chat"}}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		conn, err := upgrader.Upgrade(w, req, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		for {
+			messageType, data, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+			conn.WriteMessage(messageType, data)
+		}
+	}))
+	defer ts.Close()
+
+	url := "ws" + strings.TrimPrefix(ts.URL, "http")
+	conn, _, err := Dial(url, http.Header{"Sec-WebSocket-Protocol": {"chat"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	if conn.Subprotocol() != "chat" {
+		t.Fatalf("expect subprotocol chat, but got %q", conn.Subprotocol())
+	}
+	_ = conn.WriteMessage(TextMessage, []byte("echo"))
+	if _, data, err := conn.ReadMessage(); err != nil || string(data) != "echo" {
+		t.Fatalf("unexpected echo %q %v", data, err)
+	}
+
+	res, err := http.Get(ts.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+	if res.StatusCode != http.StatusBadRequest {
+		t.Fatalf("expect 400 for a plain request, but got %d", res.StatusCode)
+	}
+}
+
+func TestConn_AnnouncedLengthOverLimit(t *testing.T) {
+	s, c := net.Pipe()
+	server := NewConn(s, nil, true)
+	go io.Copy(io.Discard, c)
+	// a masked binary frame announcing 2^40 bytes, none of which is sent
+	go c.Write([]byte{finBit | BinaryMessage, maskBit | 127, 0, 0, 1, 0, 0, 0, 0, 0})
+
+	_, _, err := server.ReadMessage()
+	if ce, ok := err.(*CloseError); !ok || ce.Code != CloseMessageTooBig {
+		t.Fatalf("expect close 1009, but got %v", err)
+	}
+}
+
+func TestConn_ControlFrameTooLarge(t *testing.T) {
+	s, c := net.Pipe()
+	server := NewConn(s, nil, true)
+	go io.Copy(io.Discard, c)
+	go c.Write([]byte{finBit | PingMessage, maskBit | 126, 0xff, 0xff})
+
+	_, _, err := server.ReadMessage()
+	if ce, ok := err.(*CloseError); !ok || ce.Code != CloseProtocolError {
+		t.Fatalf("expect close 1002, but got %v", err)
+	}
+}
@@ -0,0 +1,159 @@
+package ws
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"io"
+)
+
+// opcodes of RFC 6455 section 5.2
+const (
+	continuationFrame = 0
+	TextMessage       = 1
+	BinaryMessage     = 2
+	CloseMessage      = 8
+	PingMessage       = 9
+	PongMessage       = 10
+)
+
+const (
+	finBit  = 1 << 7
+	rsvBits = 7 << 4
+	maskBit = 1 << 7
+
+	maxControlPayload = 125
+
+	// payloads up to this size are allocated at once, larger ones grow as the bytes arrive
+	preallocSize = 4096
+)
+
+var (
+	errFrameTooLarge   = errors.New("ws: frame payload too large")
+	errControlTooLarge = errors.New("ws: control frame payload too large")
+)
+
+type frame struct {
+	fin     bool
+	rsv     byte
+	opcode  byte
+	masked  bool
+	payload []byte // already unmasked
+}
+
+func isControl(opcode byte) bool {
+	return opcode&0x8 != 0
+}
+
+// readFrame reads one frame, limit < 0 means no limit on the payload length of data frames.
+// The announced length is checked before reading the payload, which is read incrementally
+// so a peer can not make us allocate more than it actually sends
+func readFrame(r io.Reader, limit int64) (*frame, error) {
+	var head [2]byte
+	if _, err := io.ReadFull(r, head[:]); err != nil {
+		return nil, err
+	}
+	f := &frame{
+		fin:    head[0]&finBit != 0,
+		rsv:    head[0] & rsvBits,
+		opcode: head[0] & 0xf,
+		masked: head[1]&maskBit != 0,
+	}
+
+	length := int64(head[1] & 0x7f)
+	switch length {
+	case 126:
+		var ext [2]byte
+		if _, err := io.ReadFull(r, ext[:]); err != nil {
+			return nil, err
+		}
+		length = int64(binary.BigEndian.Uint16(ext[:]))
+	case 127:
+		var ext [8]byte
+		if _, err := io.ReadFull(r, ext[:]); err != nil {
+			return nil, err
+		}
+		u := binary.BigEndian.Uint64(ext[:])
+		if u>>63 != 0 {
+			return nil, errFrameTooLarge
+		}
+		length = int64(u)
+	}
+	if isControl(f.opcode) {
+		if length > maxControlPayload {
+			return nil, errControlTooLarge
+		}
+	} else if limit >= 0 && length > limit {
+		return nil, errFrameTooLarge
+	}
+
+	var key [4]byte
+	if f.masked {
+		if _, err := io.ReadFull(r, key[:]); err != nil {
+			return nil, err
+		}
+	}
+	var buf bytes.Buffer
+	if length <= preallocSize {
+		buf.Grow(int(length))
+	}
+	if _, err := io.CopyN(&buf, r, length); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return nil, err
+	}
+	f.payload = buf.Bytes()
+	if f.masked {
+		maskBytes(key, f.payload)
+	}
+	return f, nil
+}
+
+// writeFrame writes one frame, the payload is masked with a random key if mask is true
+func writeFrame(w io.Writer, fin bool, opcode byte, mask bool, payload []byte) error {
+	buf := make([]byte, 0, 14+len(payload))
+	b0 := opcode
+	if fin {
+		b0 |= finBit
+	}
+	var b1 byte
+	if mask {
+		b1 = maskBit
+	}
+
+	length := len(payload)
+	switch {
+	case length <= 125:
+		buf = append(buf, b0, b1|byte(length))
+	case length <= 0xffff:
+		buf = append(buf, b0, b1|126, 0, 0)
+		binary.BigEndian.PutUint16(buf[2:], uint16(length))
+	default:
+		buf = append(buf, b0, b1|127, 0, 0, 0, 0, 0, 0, 0, 0)
+		binary.BigEndian.PutUint64(buf[2:], uint64(length))
+	}
+
+	if mask {
+		var key [4]byte
+		if _, err := io.ReadFull(rand.Reader, key[:]); err != nil {
+			return err
+		}
+		buf = append(buf, key[:]...)
+		start := len(buf)
+		buf = append(buf, payload...)
+		maskBytes(key, buf[start:])
+	} else {
+		buf = append(buf, payload...)
+	}
+	_, err := w.Write(buf)
+	return err
+}
+
+// maskBytes masks or unmasks b in place, the operation is its own inverse
+func maskBytes(key [4]byte, b []byte) {
+	for i := range b {
+		b[i] ^= key[i&3]
+	}
+}
@@ -0,0 +1,213 @@
+package ws
+
+import (
+	"bufio"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/tls"
+	"encoding/base64"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// the magic GUID of RFC 6455 section 1.3
+const acceptGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+var ErrBadHandshake = errors.New("ws: bad handshake")
+
+// Upgrader upgrades HTTP requests to WebSocket connections
+type Upgrader struct {
+	// Subprotocols are the supported subprotocols in the order of preference
+	Subprotocols []string
+	// CheckOrigin returns true if the Origin of the request is accepted,
+	// nil means the Origin host must equal the Host header
+	CheckOrigin func(req *http.Request) bool
+	// ReadLimit is the ReadLimit of upgraded connections, 0 means DefaultReadLimit
+	ReadLimit int64
+}
+
+func acceptKey(key string) string {
+	h := sha1.New()
+	h.Write([]byte(key + acceptGUID))
+	return base64.StdEncoding.EncodeToString(h.Sum(nil))
+}
+
+// headerContains reports whether the comma separated header has the token, case insensitive
+func headerContains(header http.Header, name string, token string) bool {
+	for _, value := range header.Values(name) {
+		for _, item := range strings.Split(value, ",") {
+			if strings.EqualFold(strings.TrimSpace(item), token) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func sameOrigin(req *http.Request) bool {
+	origin := req.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+	u, err := url.Parse(origin)
+	if err != nil {
+		return false
+	}
+	return strings.EqualFold(u.Host, req.Host)
+}
+
+func (u *Upgrader) fail(w http.ResponseWriter, code int, reason string) error {
+	w.Header().Set("Sec-WebSocket-Version", "13")
+	http.Error(w, http.StatusText(code), code)
+	return errors.New("ws: " + reason)
+}
+
+// Upgrade performs the opening handshake and hijacks the connection.
+// On failure an HTTP error has been replied and the error is returned
+func (u *Upgrader) Upgrade(w http.ResponseWriter, req *http.Request, header http.Header) (*Conn, error) {
+	if req.Method != http.MethodGet {
+		return nil, u.fail(w, http.StatusMethodNotAllowed, "method is not GET")
+	}
+	if !headerContains(req.Header, "Connection", "upgrade") || !headerContains(req.Header, "Upgrade", "websocket") {
+		return nil, u.fail(w, http.StatusBadRequest, "not a websocket handshake")
+	}
+	if req.Header.Get("Sec-WebSocket-Version") != "13" {
+		return nil, u.fail(w, http.StatusBadRequest, "unsupported version")
+	}
+	key := req.Header.Get("Sec-WebSocket-Key")
+	if decoded, err := base64.StdEncoding.DecodeString(key); err != nil || len(decoded) != 16 {
+		return nil, u.fail(w, http.StatusBadRequest, "bad Sec-WebSocket-Key")
+	}
+	checkOrigin := u.CheckOrigin
+	if checkOrigin == nil {
+		checkOrigin = sameOrigin
+	}
+	if !checkOrigin(req) {
+		return nil, u.fail(w, http.StatusForbidden, "origin not allowed")
+	}
+
+	hijacker, ok := w.(http.Hijacker)
+	if !ok {
+		return nil, u.fail(w, http.StatusInternalServerError, "response does not implement http.Hijacker")
+	}
+	subproto := u.selectSubprotocol(req)
+	netConn, brw, err := hijacker.Hijack()
+	if err != nil {
+		return nil, err
+	}
+
+	var b strings.Builder
+	b.WriteString("HTTP/1.1 101 Switching Protocols\r\nUpgrade: websocket\r\nConnection: Upgrade\r\n")
+	b.WriteString("Sec-WebSocket-Accept: " + acceptKey(key) + "\r\n")
+	if subproto != "" {
+		b.WriteString("Sec-WebSocket-Protocol: " + subproto + "\r\n")
+	}
+	for name, values := range header {
+		for _, value := range values {
+			b.WriteString(name + ": " + value + "\r\n")
+		}
+	}
+	b.WriteString("\r\n")
+	if _, err := io.WriteString(netConn, b.String()); err != nil {
+		netConn.Close()
+		return nil, err
+	}
+
+	c := NewConn(netConn, brw.Reader, true)
+	c.subproto = subproto
+	if u.ReadLimit > 0 {
+		c.ReadLimit = u.ReadLimit
+	}
+	return c, nil
+}
+
+func (u *Upgrader) selectSubprotocol(req *http.Request) string {
+	for _, supported := range u.Subprotocols {
+		if headerContains(req.Header, "Sec-WebSocket-Protocol", supported) {
+			return supported
+		}
+	}
+	return ""
+}
+
+// Dial opens a client connection to a ws:// or wss:// url
+func Dial(rawurl string, header http.Header) (*Conn, *http.Response, error) {
+	u, err := url.Parse(rawurl)
+	if err != nil {
+		return nil, nil, err
+	}
+	var netConn net.Conn
+	switch u.Scheme {
+	case "ws":
+		netConn, err = net.Dial("tcp", hostPort(u, "80"))
+	case "wss":
+		netConn, err = tls.Dial("tcp", hostPort(u, "443"), &tls.Config{ServerName: u.Hostname()})
+	default:
+		return nil, nil, errors.New("ws: bad scheme " + u.Scheme)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	c, res, err := clientHandshake(netConn, u, header)
+	if err != nil {
+		netConn.Close()
+	}
+	return c, res, err
+}
+
+func hostPort(u *url.URL, port string) string {
+	if u.Port() != "" {
+		return u.Host
+	}
+	return net.JoinHostPort(u.Hostname(), port)
+}
+
+func clientHandshake(netConn net.Conn, u *url.URL, header http.Header) (*Conn, *http.Response, error) {
+	nonce := make([]byte, 16)
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, nil, err
+	}
+	key := base64.StdEncoding.EncodeToString(nonce)
+
+	req := &http.Request{
+		Method:     http.MethodGet,
+		URL:        &url.URL{Scheme: "http", Host: u.Host, Path: u.Path, RawPath: u.RawPath, RawQuery: u.RawQuery},
+		Proto:      "HTTP/1.1",
+		ProtoMajor: 1,
+		ProtoMinor: 1,
+		Header:     make(http.Header),
+		Host:       u.Host,
+	}
+	for name, values := range header {
+		for _, value := range values {
+			req.Header.Add(name, value)
+		}
+	}
+	req.Header.Set("Upgrade", "websocket")
+	req.Header.Set("Connection", "Upgrade")
+	req.Header.Set("Sec-WebSocket-Key", key)
+	req.Header.Set("Sec-WebSocket-Version", "13")
+	if err := req.Write(netConn); err != nil {
+		return nil, nil, err
+	}
+
+	br := bufio.NewReader(netConn)
+	res, err := http.ReadResponse(br, req)
+	if err != nil {
+		return nil, nil, err
+	}
+	if res.StatusCode != http.StatusSwitchingProtocols ||
+		!headerContains(res.Header, "Upgrade", "websocket") ||
+		res.Header.Get("Sec-WebSocket-Accept") != acceptKey(key) {
+		return nil, res, ErrBadHandshake
+	}
+
+	c := NewConn(netConn, br, false)
+	c.subproto = res.Header.Get("Sec-WebSocket-Protocol")
+	return c, res, nil
+}